@@ -0,0 +1,194 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DataReplaceInlineSpec defines the desired state of DataReplaceInline
+type DataReplaceInlineSpec struct {
+	// Manifests are raw Kubernetes manifests rendered and applied by the
+	// operator. Placeholders of the form {{ <provider>:<secret>:<data> }}
+	// are replaced inline with data resolved from the external provider
+	// configured by the named Secret before the manifest is applied.
+	// +kubebuilder:validation:MinItems=1
+	Manifests []string `json:"manifests"`
+
+	// Verification configures cosign signature verification of the
+	// manifests. When set, a manifest is only rendered and applied after its
+	// signature checks out against the configured public key.
+	// +optional
+	Verification *VerificationSpec `json:"verification,omitempty"`
+
+	// Outputs declares non-sensitive values published in status.outputs
+	// after every successful sync, for consumption by other DataReplaceInline
+	// resources through references of the form
+	// ${dri:<name>@status.outputs.<key>}.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// RefreshInterval, when set, re-renders and re-applies the manifests on
+	// this interval even without spec changes, so values rotated at the
+	// provider propagate to the target resources periodically.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// Rollout configures workloads to restart when the rendered output
+	// changes, so pods consuming a managed Secret or ConfigMap pick up
+	// rotated credentials automatically.
+	// +optional
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+
+	// Debug configures debugging helpers for this resource.
+	// +optional
+	Debug *DebugSpec `json:"debug,omitempty"`
+}
+
+// RolloutSpec configures rollout propagation of rendered changes.
+type RolloutSpec struct {
+	// Annotation is the pod template annotation set to the checksum of the
+	// rendered manifests. Defaults to "datareplace.jaberchez.io/checksum".
+	// +optional
+	Annotation string `json:"annotation,omitempty"`
+
+	// Targets are the workloads whose pod template annotation is bumped
+	// when the checksum changes.
+	// +kubebuilder:validation:MinItems=1
+	Targets []RolloutTarget `json:"targets"`
+}
+
+// RolloutTarget identifies a workload to restart on rendered changes.
+type RolloutTarget struct {
+	// Kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind string `json:"kind"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+
+	// Namespace of the workload. Defaults to the resource namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// VerificationSpec configures cosign signature verification of manifests.
+type VerificationSpec struct {
+	// SecretName names a Secret in the resource namespace holding the cosign
+	// public key under the key "cosign.pub".
+	SecretName string `json:"secretName"`
+
+	// Signatures holds one base64 signature per manifest, in manifest order,
+	// as produced by "cosign sign-blob".
+	// +kubebuilder:validation:MinItems=1
+	Signatures []string `json:"signatures"`
+}
+
+// DebugSpec configures debugging helpers.
+type DebugSpec struct {
+	// RenderedManifests publishes the rendered manifests in the status with
+	// every resolved value replaced by a masked sha256 prefix, so rendering
+	// problems can be inspected without exposing secrets.
+	// +optional
+	RenderedManifests bool `json:"renderedManifests,omitempty"`
+
+	// ExportTo names a ConfigMap in the resource namespace that receives the
+	// redacted rendered manifests, the placeholder plan and resolution
+	// provenance after every sync, so support can debug tenant issues
+	// without operator log access.
+	// +optional
+	ExportTo string `json:"exportTo,omitempty"`
+}
+
+// DataReplaceInlineStatus defines the observed state of DataReplaceInline
+type DataReplaceInlineStatus struct {
+	// Ready is true when every manifest rendered and applied successfully
+	// during the last reconcile.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// TargetKind lists the kinds of the resources applied from the rendered
+	// manifests, in manifest order.
+	// +optional
+	TargetKind string `json:"targetKind,omitempty"`
+
+	// TargetName lists the names of the resources applied from the rendered
+	// manifests, in manifest order.
+	// +optional
+	TargetName string `json:"targetName,omitempty"`
+
+	// LastSyncTime is the time of the last successful render and apply.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Message describes the outcome of the last reconcile, including the
+	// failure reason when Ready is false.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// RenderedManifests holds the rendered manifests with every resolved
+	// value masked. Only populated when spec.debug.renderedManifests is set.
+	// +optional
+	RenderedManifests []string `json:"renderedManifests,omitempty"`
+
+	// SyncAttempts counts the reconciles attempted for this resource.
+	// +optional
+	SyncAttempts int64 `json:"syncAttempts,omitempty"`
+
+	// ConsecutiveFailures counts the reconciles that failed since the last
+	// successful sync.
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// LastAppliedHash is the sha256 of the rendered manifests applied by the
+	// last successful sync, so drift between syncs can be spotted at a
+	// glance.
+	// +optional
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+
+	// Outputs are the declared non-sensitive outputs of the last successful
+	// sync, readable by other DataReplaceInline resources.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// LeaseExpiry is when the earliest lease of a dynamic secret resolved by
+	// the last sync runs out. The resource is reconciled again before this
+	// time so rotated credentials are re-rendered.
+	// +optional
+	LeaseExpiry *metav1.Time `json:"leaseExpiry,omitempty"`
+
+	// Conditions represent the latest observations of the resource state.
+	// The Degraded condition carries a machine-readable reason for the last
+	// failure.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=dri
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Target Kind",type=string,JSONPath=`.status.targetKind`
+//+kubebuilder:printcolumn:name="Target Name",type=string,JSONPath=`.status.targetName`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+//+kubebuilder:printcolumn:name="Message",type=string,priority=1,JSONPath=`.status.message`
+
+// DataReplaceInline is the Schema for the datareplaceinlines API
+type DataReplaceInline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataReplaceInlineSpec   `json:"spec,omitempty"`
+	Status DataReplaceInlineStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DataReplaceInlineList contains a list of DataReplaceInline
+type DataReplaceInlineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DataReplaceInline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DataReplaceInline{}, &DataReplaceInlineList{})
+}
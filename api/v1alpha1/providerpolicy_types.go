@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderPolicySpec defines which provider backends each namespace may use.
+type ProviderPolicySpec struct {
+	// Rules map namespaces to the providers they are allowed to use. A
+	// namespace matched by any rule may only use the providers listed by the
+	// rules matching it; namespaces matched by no rule are unrestricted.
+	// +kubebuilder:validation:MinItems=1
+	Rules []ProviderPolicyRule `json:"rules"`
+}
+
+// ProviderPolicyRule allows a set of namespaces to use a set of providers.
+type ProviderPolicyRule struct {
+	// Namespaces this rule applies to. The entry "*" matches every
+	// namespace.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+
+	// Providers allowed for the matched namespaces. An entry is either a
+	// provider type such as "vault", allowing every instance of that type,
+	// or "<type>:<secret>" such as "vault:vault-team-a", allowing only the
+	// instance configured by that Secret. The entry "*" allows everything.
+	// +kubebuilder:validation:MinItems=1
+	Providers []string `json:"providers"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// ProviderPolicy restricts which provider backends the DataReplaceInline
+// resources of a namespace may reference, so tenants sharing one operator
+// cannot read each other's backends.
+type ProviderPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ProviderPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ProviderPolicyList contains a list of ProviderPolicy
+type ProviderPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProviderPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProviderPolicy{}, &ProviderPolicyList{})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+)
+
+// runCheck authenticates to a provider backend from a local configuration
+// and performs the same harmless connectivity check the operator readiness
+// probe uses, so credentials can be verified before they are wired into
+// manifests.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	provider := fs.String("provider", "", "Provider type to check, e.g. vault or git (required).")
+	file := fs.String("f", "",
+		"YAML file with the provider configuration, a flat map of the same keys the in-cluster Secret would hold (required).")
+	timeout := fs.Duration("timeout", 30*time.Second, "Timeout for the connectivity check.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*provider) == 0 || len(*file) == 0 {
+		return fmt.Errorf("the -provider and -f flags are required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", *file, err)
+	}
+
+	stringData := map[string]string{}
+
+	if err := yaml.Unmarshal(content, &stringData); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", *file, err)
+	}
+
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	secret.Name = *file
+
+	for k, v := range stringData {
+		secret.Data[k] = []byte(v)
+	}
+
+	handler, err := handlers.NewFromSecret(*provider, secret)
+	if err != nil {
+		return err
+	}
+
+	checker, ok := handler.(handlers.Checker)
+	if !ok {
+		return fmt.Errorf("provider %s does not support connectivity checks", *provider)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	if err := checker.Check(ctx); err != nil {
+		return fmt.Errorf("%s check failed: %w", *provider, err)
+	}
+
+	fmt.Printf("%s: OK\n", *provider)
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// runImportESO translates External Secrets Operator resources into the
+// equivalent DataReplaceInline setup, easing migration for teams
+// consolidating on this operator:
+//
+//   - a SecretStore or ClusterSecretStore with a vault provider becomes a
+//     vault provider Secret (the token must be filled in by hand, since the
+//     store only references it);
+//   - an ExternalSecret becomes a DataReplaceInline whose manifest creates
+//     the target Secret with one placeholder per data entry.
+//
+// The converted manifests are printed to stdout for review; nothing is
+// applied.
+func runImportESO(args []string) error {
+	fs := flag.NewFlagSet("import-eso", flag.ContinueOnError)
+	file := fs.String("f", "", "YAML file with ExternalSecret and SecretStore documents (required).")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		return fmt.Errorf("the -f flag is required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", *file, err)
+	}
+
+	converted := 0
+
+	for _, document := range strings.Split(string(content), "\n---") {
+		if len(strings.TrimSpace(document)) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+
+		if err := yaml.Unmarshal([]byte(document), obj); err != nil {
+			return fmt.Errorf("unable to parse document: %w", err)
+		}
+
+		var out string
+
+		switch obj.GetKind() {
+		case "SecretStore", "ClusterSecretStore":
+			out, err = convertSecretStore(obj)
+		case "ExternalSecret":
+			out, err = convertExternalSecret(obj)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+
+		if converted > 0 {
+			fmt.Println("---")
+		}
+
+		fmt.Print(out)
+		converted++
+	}
+
+	if converted == 0 {
+		return fmt.Errorf("no ExternalSecret or SecretStore documents found in %s", *file)
+	}
+
+	return nil
+}
+
+// convertSecretStore emits a vault provider Secret for a (Cluster)SecretStore
+// backed by Vault.
+func convertSecretStore(obj *unstructured.Unstructured) (string, error) {
+	server, ok, _ := unstructured.NestedString(obj.Object, "spec", "provider", "vault", "server")
+	if !ok {
+		return "", fmt.Errorf("only vault-backed stores can be converted")
+	}
+
+	tokenRef, _, _ := unstructured.NestedString(obj.Object,
+		"spec", "provider", "vault", "auth", "tokenSecretRef", "name")
+
+	comment := "# Fill in the token"
+	if len(tokenRef) > 0 {
+		comment = fmt.Sprintf("# Fill in the token (the store referenced Secret %q)", tokenRef)
+	}
+
+	return fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+stringData:
+  address: %s
+  %s
+  token: ""
+`, obj.GetName(), server, comment), nil
+}
+
+// convertExternalSecret emits a DataReplaceInline creating the target Secret
+// with one placeholder per data entry.
+func convertExternalSecret(obj *unstructured.Unstructured) (string, error) {
+	storeName, ok, _ := unstructured.NestedString(obj.Object, "spec", "secretStoreRef", "name")
+	if !ok {
+		return "", fmt.Errorf("spec.secretStoreRef.name is required")
+	}
+
+	targetName, ok, _ := unstructured.NestedString(obj.Object, "spec", "target", "name")
+	if !ok {
+		targetName = obj.GetName()
+	}
+
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", "data")
+	if len(entries) == 0 {
+		return "", fmt.Errorf("spec.data is empty; dataFrom imports are not supported")
+	}
+
+	var lines []string
+
+	for _, entry := range entries {
+		item, ok := entry.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("unexpected spec.data entry")
+		}
+
+		secretKey, _, _ := unstructured.NestedString(item, "secretKey")
+		remoteKey, _, _ := unstructured.NestedString(item, "remoteRef", "key")
+		property, _, _ := unstructured.NestedString(item, "remoteRef", "property")
+
+		if len(secretKey) == 0 || len(remoteKey) == 0 || len(property) == 0 {
+			return "", fmt.Errorf("spec.data entries need secretKey, remoteRef.key and remoteRef.property")
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("        %s: '{{ vault:%s:%s#%s }}'", secretKey, storeName, remoteKey, property))
+	}
+
+	return fmt.Sprintf(`apiVersion: datareplace.jaberchez.io/v1alpha1
+kind: DataReplaceInline
+metadata:
+  name: %s
+spec:
+  manifests:
+    - |
+      apiVersion: v1
+      kind: Secret
+      metadata:
+        name: %s
+      stringData:
+%s
+`, obj.GetName(), targetName, strings.Join(lines, "\n")), nil
+}
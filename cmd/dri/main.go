@@ -0,0 +1,57 @@
+// The dri command offers local tooling around DataReplaceInline resources,
+// starting with offline rendering of manifests, so authors can iterate on
+// placeholders without deploying to a cluster.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: dri <command> [flags]
+
+Commands:
+  render    Render the manifests of a DataReplaceInline YAML locally.
+  validate  Lint the manifests of a DataReplaceInline YAML without resolving.
+  check     Verify connectivity to a provider from a local configuration.
+  import-eso  Convert External Secrets Operator resources to this operator.
+  scaffold  Print ready-to-edit provider configuration Secret manifests.
+
+Run "dri <command> -h" for the flags of a command.
+`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "render":
+		err = runRender(os.Args[2:])
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "check":
+		err = runCheck(os.Args[2:])
+	case "import-eso":
+		err = runImportESO(os.Args[2:])
+	case "scaffold":
+		err = runScaffold(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
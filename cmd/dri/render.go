@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+)
+
+// runRender renders the manifests of a DataReplaceInline YAML file locally
+// and prints them to stdout, separated by YAML document markers.
+//
+// Placeholders resolve from two local sources, tried in order:
+//
+//   - a stub values file (--stub-values) mapping
+//     "<provider>:<secret>:<data>" keys to fixed values, for fully offline
+//     runs;
+//   - stub provider Secrets (--provider-secrets), a directory with one
+//     <secret>.yaml per provider Secret holding the same keys the in-cluster
+//     Secret would, resolved through the real provider handlers.
+func runRender(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	file := fs.String("f", "", "DataReplaceInline YAML file to render (required).")
+	secretsDir := fs.String("provider-secrets", "",
+		"Directory with one <secret>.yaml stub per provider Secret, each a flat map of the Secret string data.")
+	stubValuesFile := fs.String("stub-values", "",
+		"YAML file mapping \"<provider>:<secret>:<data>\" to fixed values, resolved without contacting any provider.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		return fmt.Errorf("the -f flag is required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", *file, err)
+	}
+
+	cr := &datareplacev1alpha1.DataReplaceInline{}
+
+	if err := yaml.Unmarshal(content, cr); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", *file, err)
+	}
+
+	resolver := &localResolver{
+		secretsDir: *secretsDir,
+		stubs:      map[string]string{},
+		handlers:   map[string]handlers.Handler{},
+	}
+
+	if len(*stubValuesFile) > 0 {
+		stubContent, err := os.ReadFile(*stubValuesFile)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", *stubValuesFile, err)
+		}
+
+		if err := yaml.Unmarshal(stubContent, &resolver.stubs); err != nil {
+			return fmt.Errorf("unable to parse %s: %w", *stubValuesFile, err)
+		}
+	}
+
+	ctx := context.Background()
+
+	for i, manifest := range cr.Spec.Manifests {
+		rendered, err := resolver.render(ctx, manifest)
+		if err != nil {
+			return fmt.Errorf("manifest %d: %w", i, err)
+		}
+
+		if i > 0 {
+			fmt.Println("---")
+		}
+
+		fmt.Println(rendered)
+	}
+
+	return nil
+}
+
+// localResolver resolves placeholders from stub values and stub provider
+// Secrets instead of a cluster.
+type localResolver struct {
+	secretsDir string
+	stubs      map[string]string
+	handlers   map[string]handlers.Handler
+}
+
+func (r *localResolver) render(ctx context.Context, manifest string) (string, error) {
+	lines := strings.Split(manifest, "\n")
+
+	for i, line := range lines {
+		for _, match := range placeholder.Find(line) {
+			value, err := r.resolve(ctx, match)
+			if err != nil {
+				return "", fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err)
+			}
+
+			line = strings.Replace(line, match.Raw, value, 1)
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (r *localResolver) resolve(ctx context.Context, match placeholder.Placeholder) (string, error) {
+	if value, ok := r.stubs[match.Provider+":"+match.Secret+":"+match.Data]; ok {
+		return value, nil
+	}
+
+	handler, err := r.handlerFor(match.Provider, match.Secret)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := handler.GetData(ctx, match.Data)
+	if err != nil {
+		return "", err
+	}
+
+	return value.Reveal(), nil
+}
+
+// handlerFor builds (and caches) the provider handler for a stub Secret file
+// named <secret>.yaml in the secrets directory.
+func (r *localResolver) handlerFor(provider, secretName string) (handlers.Handler, error) {
+	key := provider + "/" + secretName
+
+	if handler, ok := r.handlers[key]; ok {
+		return handler, nil
+	}
+
+	if len(r.secretsDir) == 0 {
+		return nil, fmt.Errorf("no stub value found and --provider-secrets is not set")
+	}
+
+	path := filepath.Join(r.secretsDir, secretName+".yaml")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read stub provider secret %s: %w", path, err)
+	}
+
+	stringData := map[string]string{}
+
+	if err := yaml.Unmarshal(content, &stringData); err != nil {
+		return nil, fmt.Errorf("unable to parse stub provider secret %s: %w", path, err)
+	}
+
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	secret.Name = secretName
+
+	for k, v := range stringData {
+		secret.Data[k] = []byte(v)
+	}
+
+	handler, err := handlers.NewFromSecret(provider, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	r.handlers[key] = handler
+
+	return handler, nil
+}
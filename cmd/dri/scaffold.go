@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+)
+
+// runScaffold prints a ready-to-edit provider configuration Secret for each
+// requested backend. The emitted keys come from the same field descriptors
+// the handler constructors validate against, so the scaffold never drifts
+// from what the operator accepts.
+func runScaffold(args []string) error {
+	flags := flag.NewFlagSet("scaffold", flag.ExitOnError)
+	provider := flags.String("provider", "", "provider to scaffold; defaults to every known provider")
+	namespace := flags.String("n", "default", "namespace of the scaffolded Secret")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	providerList := handlers.KnownProviders()
+
+	if len(*provider) > 0 {
+		providerList = []string{*provider}
+	}
+
+	for i, p := range providerList {
+		if i > 0 {
+			fmt.Println("---")
+		}
+
+		if err := scaffoldProvider(p, *namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaffoldProvider writes the Secret manifest for one provider to stdout.
+func scaffoldProvider(provider, namespace string) error {
+	fields, err := handlers.ConfigFields(provider)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "apiVersion: v1\n")
+	fmt.Fprintf(&b, "kind: Secret\n")
+	fmt.Fprintf(&b, "metadata:\n")
+	fmt.Fprintf(&b, "  name: %s-config\n", provider)
+	fmt.Fprintf(&b, "  namespace: %s\n", namespace)
+	fmt.Fprintf(&b, "type: Opaque\n")
+	fmt.Fprintf(&b, "stringData:\n")
+
+	for _, field := range fields {
+		requirement := "optional"
+
+		if field.Required {
+			requirement = "required"
+		}
+
+		fmt.Fprintf(&b, "  # %s (%s)\n", field.Comment, requirement)
+
+		if field.Required {
+			fmt.Fprintf(&b, "  %s: \"\"\n", field.Name)
+		} else {
+			fmt.Fprintf(&b, "  # %s: \"\"\n", field.Name)
+		}
+	}
+
+	_, err = os.Stdout.WriteString(b.String())
+
+	return err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+)
+
+// anyPlaceholder loosely matches anything that looks like a placeholder, so
+// malformed ones that the strict pattern skips are reported instead of being
+// applied verbatim.
+var anyPlaceholder = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// runValidate lints the manifests of a DataReplaceInline YAML file without
+// resolving anything: placeholder syntax, known provider names and YAML
+// validity of each manifest once placeholders are substituted. Intended for
+// CI, it exits non-zero when any manifest has findings — the same errors the
+// controller would otherwise only report at runtime.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	file := fs.String("f", "", "DataReplaceInline YAML file to validate (required).")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		return fmt.Errorf("the -f flag is required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", *file, err)
+	}
+
+	cr := &datareplacev1alpha1.DataReplaceInline{}
+
+	if err := yaml.Unmarshal(content, cr); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", *file, err)
+	}
+
+	if len(cr.Spec.Manifests) == 0 {
+		return fmt.Errorf("spec.manifests is empty")
+	}
+
+	findings := 0
+
+	for i, manifest := range cr.Spec.Manifests {
+		for _, finding := range validateManifest(manifest) {
+			fmt.Printf("manifest %d: %s\n", i, finding)
+			findings++
+		}
+	}
+
+	if findings > 0 {
+		return fmt.Errorf("%d findings", findings)
+	}
+
+	fmt.Printf("%d manifests OK\n", len(cr.Spec.Manifests))
+
+	return nil
+}
+
+// validateManifest returns the findings for a single manifest.
+func validateManifest(manifest string) []string {
+	var findings []string
+
+	known := map[string]bool{}
+	for _, provider := range handlers.KnownProviders() {
+		known[provider] = true
+	}
+
+	lineNumber := 0
+
+	for _, line := range strings.Split(manifest, "\n") {
+		lineNumber++
+
+		matches := placeholder.Find(line)
+
+		parsed := map[string]bool{}
+
+		for _, match := range matches {
+			parsed[match.Raw] = true
+
+			if !known[match.Provider] {
+				findings = append(findings,
+					fmt.Sprintf("line %d: unknown provider %q in placeholder %s", lineNumber, match.Provider, match.Raw))
+			}
+		}
+
+		// Anything placeholder-shaped that the strict pattern did not parse
+		// would be applied verbatim by the controller.
+		for _, loose := range anyPlaceholder.FindAllString(line, -1) {
+			if !parsed[loose] {
+				findings = append(findings,
+					fmt.Sprintf("line %d: malformed placeholder %s, expected {{ <provider>:<secret>:<data> }}", lineNumber, loose))
+			}
+		}
+	}
+
+	// Substitute placeholders with a dummy value and check the result still
+	// parses as YAML, since a resolved value can't fix broken structure.
+	substituted := placeholder.Pattern().ReplaceAllString(manifest, "masked")
+
+	var document map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(substituted), &document); err != nil {
+		findings = append(findings, fmt.Sprintf("not valid YAML after substitution: %v", err))
+	}
+
+	return findings
+}
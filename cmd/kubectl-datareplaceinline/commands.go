@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// refreshAnnotation is bumped by the refresh command to force a reconcile.
+const refreshAnnotation = "datareplace.jaberchez.io/refresh"
+
+// loadCR parses the shared name/namespace arguments of a subcommand and
+// fetches the resource.
+func loadCR(fs *flag.FlagSet, args []string) (client.Client, *datareplacev1alpha1.DataReplaceInline, error) {
+	namespace := fs.String("n", "default", "Namespace of the resource.")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if fs.NArg() != 1 {
+		return nil, nil, fmt.Errorf("exactly one resource name is required")
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cr := &datareplacev1alpha1.DataReplaceInline{}
+
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: *namespace, Name: fs.Arg(0)}, cr); err != nil {
+		return nil, nil, err
+	}
+
+	return c, cr, nil
+}
+
+// runStatus prints the conditions, targets and provenance of a resource.
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+
+	_, cr, err := loadCR(fs, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Name:                 %s/%s\n", cr.Namespace, cr.Name)
+	fmt.Printf("Ready:                %t\n", cr.Status.Ready)
+	fmt.Printf("Message:              %s\n", cr.Status.Message)
+	fmt.Printf("Targets:              %s\n", formatTargets(cr))
+	fmt.Printf("Sync attempts:        %d\n", cr.Status.SyncAttempts)
+	fmt.Printf("Consecutive failures: %d\n", cr.Status.ConsecutiveFailures)
+	fmt.Printf("Last applied hash:    %s\n", cr.Status.LastAppliedHash)
+
+	if cr.Status.LastSyncTime != nil {
+		fmt.Printf("Last sync:            %s\n", cr.Status.LastSyncTime.Format(time.RFC3339))
+	}
+
+	if len(cr.Status.Conditions) > 0 {
+		fmt.Println("Conditions:")
+
+		for _, condition := range cr.Status.Conditions {
+			fmt.Printf("  %s=%s (%s) %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	return nil
+}
+
+func formatTargets(cr *datareplacev1alpha1.DataReplaceInline) string {
+	kinds := strings.Split(cr.Status.TargetKind, ",")
+	names := strings.Split(cr.Status.TargetName, ",")
+
+	if len(kinds) != len(names) || len(cr.Status.TargetKind) == 0 {
+		return cr.Status.TargetKind
+	}
+
+	targets := make([]string, len(kinds))
+	for i := range kinds {
+		targets[i] = kinds[i] + "/" + names[i]
+	}
+
+	return strings.Join(targets, ", ")
+}
+
+// runRefresh bumps the refresh annotation so the controller reconciles the
+// resource again.
+func runRefresh(args []string) error {
+	fs := flag.NewFlagSet("refresh", flag.ContinueOnError)
+
+	c, cr, err := loadCR(fs, args)
+	if err != nil {
+		return err
+	}
+
+	patched := cr.DeepCopy()
+
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+
+	patched.Annotations[refreshAnnotation] = time.Now().Format(time.RFC3339)
+
+	if err := c.Patch(context.Background(), patched, client.MergeFrom(cr)); err != nil {
+		return fmt.Errorf("unable to annotate %s/%s: %w", cr.Namespace, cr.Name, err)
+	}
+
+	fmt.Printf("%s/%s refreshed\n", cr.Namespace, cr.Name)
+
+	return nil
+}
+
+// runDiff compares the redacted rendered manifests published in the status
+// against the live objects. Resolved values are masked on the rendered side,
+// and Secret data is masked on the live side, so the output never contains
+// secret material.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+
+	c, cr, err := loadCR(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(cr.Status.RenderedManifests) == 0 {
+		return fmt.Errorf("no rendered manifests in status; set spec.debug.renderedManifests to true and wait for a sync")
+	}
+
+	for _, rendered := range cr.Status.RenderedManifests {
+		obj := &unstructured.Unstructured{}
+
+		if err := yaml.Unmarshal([]byte(rendered), obj); err != nil {
+			return fmt.Errorf("unable to decode rendered manifest: %w", err)
+		}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+
+		namespace := obj.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = cr.Namespace
+		}
+
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: obj.GetName()}, live)
+		if err != nil {
+			fmt.Printf("--- %s %s: live object not found (%v)\n", obj.GetKind(), obj.GetName(), err)
+			continue
+		}
+
+		pruneLiveObject(live)
+
+		liveYAML, err := yaml.Marshal(live.Object)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("--- %s %s\n", obj.GetKind(), obj.GetName())
+		printDiff(strings.Split(strings.TrimRight(rendered, "\n"), "\n"),
+			strings.Split(strings.TrimRight(string(liveYAML), "\n"), "\n"))
+	}
+
+	return nil
+}
+
+// pruneLiveObject removes server-populated fields that would drown the diff
+// and masks Secret data.
+func pruneLiveObject(live *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(live.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(live.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(live.Object, "metadata", "uid")
+	unstructured.RemoveNestedField(live.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(live.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(live.Object, "status")
+
+	if live.GetKind() != "Secret" {
+		return
+	}
+
+	if data, ok, _ := unstructured.NestedMap(live.Object, "data"); ok {
+		for key, value := range data {
+			if encoded, ok := value.(string); ok {
+				data[key] = redact.Mask(encoded)
+			}
+		}
+
+		_ = unstructured.SetNestedMap(live.Object, data, "data")
+	}
+}
+
+// printDiff prints a minimal line diff between the rendered and live
+// documents: common lines unprefixed, rendered-only lines with "-" and
+// live-only lines with "+".
+func printDiff(rendered, live []string) {
+	// Longest common subsequence over the two line slices.
+	lcs := make([][]int, len(rendered)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(live)+1)
+	}
+
+	for i := len(rendered) - 1; i >= 0; i-- {
+		for j := len(live) - 1; j >= 0; j-- {
+			if rendered[i] == live[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(rendered) && j < len(live) {
+		switch {
+		case rendered[i] == live[j]:
+			fmt.Println("  " + rendered[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			fmt.Println("- " + rendered[i])
+			i++
+		default:
+			fmt.Println("+ " + live[j])
+			j++
+		}
+	}
+
+	for ; i < len(rendered); i++ {
+		fmt.Println("- " + rendered[i])
+	}
+
+	for ; j < len(live); j++ {
+		fmt.Println("+ " + live[j])
+	}
+}
@@ -0,0 +1,82 @@
+// The kubectl-datareplaceinline command is a kubectl plugin for inspecting
+// DataReplaceInline resources: status shows conditions and provenance, diff
+// compares the redacted rendered manifests against the live objects, and
+// refresh forces a re-reconcile.
+//
+// Install it by placing the binary on PATH; kubectl then exposes it as
+// "kubectl datareplaceinline".
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: kubectl datareplaceinline <command> [flags] <name>
+
+Commands:
+  status    Show conditions, targets and provenance of a resource.
+  diff      Diff the redacted rendered manifests against the live objects.
+  refresh   Annotate a resource to force a re-reconcile.
+
+Run "kubectl datareplaceinline <command> -h" for the flags of a command.
+`)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "status":
+		err = runStatus(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "refresh":
+		err = runRefresh(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// newClient builds a controller-runtime client from the active kubeconfig.
+func newClient() (client.Client, error) {
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := datareplacev1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return client.New(config, client.Options{Scheme: scheme})
+}
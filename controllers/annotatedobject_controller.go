@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/policy"
+)
+
+// Annotations driving reconciliation of existing ConfigMaps and Secrets that
+// cannot be migrated to the DataReplaceInline CRD.
+const (
+	// enabledAnnotation opts an object in: its data values are treated as
+	// templates containing placeholders.
+	enabledAnnotation = "datareplaceinline/enabled"
+
+	// targetAnnotation names the object the resolved result is written to.
+	// Defaults to "<name>-rendered"; naming the object itself resolves in
+	// place, which overwrites the template.
+	targetAnnotation = "datareplaceinline/target"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+
+// annotatedPredicate reconciles only objects that opted in.
+var annotatedPredicate = predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	return obj.GetAnnotations()[enabledAnnotation] == "true"
+})
+
+// resolveInline resolves every placeholder in text through the shared
+// handler pool, applying ProviderPolicy restrictions for the namespace.
+func resolveInline(ctx context.Context, pool *handlers.Pool, checker *policy.Checker, namespace, text string) (string, error) {
+	for _, match := range placeholder.Find(text) {
+		if err := checker.Allowed(ctx, namespace, match.Provider, match.Secret); err != nil {
+			return "", fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err)
+		}
+
+		handler, _, err := pool.Get(ctx, namespace, match.Provider, match.Secret)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err)
+		}
+
+		value, err := handler.GetData(ctx, match.Data)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err)
+		}
+
+		text = strings.Replace(text, match.Raw, value.Reveal(), 1)
+	}
+
+	return text, nil
+}
+
+// targetName returns the name the resolved result of obj is written to.
+func targetName(obj client.Object) string {
+	if target := obj.GetAnnotations()[targetAnnotation]; len(target) > 0 {
+		return target
+	}
+
+	return obj.GetName() + "-rendered"
+}
+
+// AnnotatedConfigMapReconciler resolves placeholders in ConfigMaps annotated
+// with datareplaceinline/enabled and writes the result into a sibling
+// ConfigMap (or in place, when the target annotation names the object
+// itself).
+type AnnotatedConfigMapReconciler struct {
+	client.Client
+	Handlers *handlers.Pool
+	Policy   *policy.Checker
+}
+
+// Reconcile renders one annotated ConfigMap.
+func (r *AnnotatedConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	source := &corev1.ConfigMap{}
+
+	if err := r.Get(ctx, req.NamespacedName, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if source.Annotations[enabledAnnotation] != "true" {
+		return ctrl.Result{}, nil
+	}
+
+	resolved := make(map[string]string, len(source.Data))
+
+	for key, value := range source.Data {
+		rendered, err := resolveInline(ctx, r.Handlers, r.Policy, source.Namespace, value)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("key %s: %w", key, err)
+		}
+
+		resolved[key] = rendered
+	}
+
+	target := &corev1.ConfigMap{}
+	target.Namespace = source.Namespace
+	target.Name = targetName(source)
+
+	operation, err := controllerutil.CreateOrUpdate(ctx, r.Client, target, func() error {
+		target.Data = resolved
+
+		// The rendered object must not opt in itself, or the two objects
+		// would render each other forever.
+		delete(target.Annotations, enabledAnnotation)
+
+		if target.Name != source.Name {
+			return controllerutil.SetControllerReference(source, target, r.Scheme())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to write rendered configmap %s/%s: %w", target.Namespace, target.Name, err)
+	}
+
+	logger.Info("rendered annotated configmap", "target", target.Name, "operation", operation)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AnnotatedConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(annotatedPredicate)).
+		Complete(r)
+}
+
+// AnnotatedSecretReconciler is the Secret counterpart of
+// AnnotatedConfigMapReconciler.
+type AnnotatedSecretReconciler struct {
+	client.Client
+	Handlers *handlers.Pool
+	Policy   *policy.Checker
+}
+
+// Reconcile renders one annotated Secret.
+func (r *AnnotatedSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	source := &corev1.Secret{}
+
+	if err := r.Get(ctx, req.NamespacedName, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if source.Annotations[enabledAnnotation] != "true" {
+		return ctrl.Result{}, nil
+	}
+
+	resolved := make(map[string][]byte, len(source.Data))
+
+	for key, value := range source.Data {
+		rendered, err := resolveInline(ctx, r.Handlers, r.Policy, source.Namespace, string(value))
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("key %s: %w", key, err)
+		}
+
+		resolved[key] = []byte(rendered)
+	}
+
+	target := &corev1.Secret{}
+	target.Namespace = source.Namespace
+	target.Name = targetName(source)
+
+	operation, err := controllerutil.CreateOrUpdate(ctx, r.Client, target, func() error {
+		target.Type = source.Type
+		target.Data = resolved
+
+		delete(target.Annotations, enabledAnnotation)
+
+		if target.Name != source.Name {
+			return controllerutil.SetControllerReference(source, target, r.Scheme())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to write rendered secret %s/%s: %w", target.Namespace, target.Name, err)
+	}
+
+	logger.Info("rendered annotated secret", "target", target.Name, "operation", operation)
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AnnotatedSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Secret{}, builder.WithPredicates(annotatedPredicate)).
+		Complete(r)
+}
@@ -0,0 +1,869 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/sync/errgroup"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/audit"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/metrics"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/notify"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/policy"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/tracing"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/verify"
+)
+
+// fieldManager identifies the operator in server-side apply patches.
+const fieldManager = "operator-data-replace-inline"
+
+// degradedCondition is the condition type carrying the classified reason of
+// the last failure.
+const degradedCondition = "Degraded"
+
+// renewalDegradedCondition reports failures of background credential
+// renewal, which degrade a provider before its token actually expires.
+const renewalDegradedCondition = "RenewalDegraded"
+
+// maxLineSize is the longest manifest line the renderer accepts. The default
+// bufio.Scanner limit of 64KB is too small for manifests with inlined base64
+// blobs.
+const maxLineSize = 10 * 1024 * 1024
+
+// renderWorkers bounds how many manifests of one resource render and apply
+// in parallel.
+const renderWorkers = 4
+
+// manifestTimeout bounds the render and apply of a single manifest.
+const manifestTimeout = 5 * time.Minute
+
+// defaultRolloutAnnotation is the pod template annotation bumped on rendered
+// changes when spec.rollout does not name one.
+const defaultRolloutAnnotation = "datareplace.jaberchez.io/checksum"
+
+// leaseRequeueLeeway is how long before a dynamic secret lease expires the
+// resource is reconciled again, and leaseRequeueFloor keeps very short
+// leases from turning into a hot loop.
+const (
+	leaseRequeueLeeway = time.Minute
+	leaseRequeueFloor  = 10 * time.Second
+)
+
+// reconcileSummary aggregates counters logged once per reconcile so
+// log-based triage doesn't have to piece together per-placeholder lines.
+// Manifests render concurrently, so placeholder recording is locked.
+type reconcileSummary struct {
+	mu            sync.Mutex
+	placeholders  int
+	providers     map[string]struct{}
+	planned       map[string]struct{}
+	plan          []string
+	bytesRendered int
+	applied       int
+	cacheHits     int
+	nextRotation  time.Time
+	renewalErrors map[string]string
+}
+
+func newReconcileSummary() *reconcileSummary {
+	return &reconcileSummary{
+		providers:     map[string]struct{}{},
+		planned:       map[string]struct{}{},
+		renewalErrors: map[string]string{},
+	}
+}
+
+func (s *reconcileSummary) recordPlaceholder(match placeholder.Placeholder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.placeholders++
+	s.providers[match.Provider] = struct{}{}
+
+	if _, ok := s.planned[match.Raw]; !ok {
+		s.planned[match.Raw] = struct{}{}
+		s.plan = append(s.plan,
+			fmt.Sprintf("%s -> provider=%s secret=%s data=%s", match.Raw, match.Provider, match.Secret, match.Data))
+	}
+}
+
+// recordCacheHit counts a resolution served from a cache: a deduplicated
+// placeholder within one manifest or a pooled handler instance.
+func (s *reconcileSummary) recordCacheHit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cacheHits++
+}
+
+func (s *reconcileSummary) cacheHitCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cacheHits
+}
+
+// recordRotation keeps the earliest lease expiry seen across the handlers
+// used this reconcile, so the request can be requeued before credentials
+// rotate.
+func (s *reconcileSummary) recordRotation(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nextRotation.IsZero() || at.Before(s.nextRotation) {
+		s.nextRotation = at
+	}
+}
+
+func (s *reconcileSummary) rotationTime() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.nextRotation
+}
+
+// recordRenewalError notes a failed background credential renewal of the
+// provider Secret, deduplicated so one flaky provider yields one message.
+func (s *reconcileSummary) recordRenewalError(provider, secretName string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.renewalErrors[fmt.Sprintf("%s/%s", provider, secretName)] = err.Error()
+}
+
+func (s *reconcileSummary) renewalText() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lines := make([]string, 0, len(s.renewalErrors))
+	for source, message := range s.renewalErrors {
+		lines = append(lines, fmt.Sprintf("%s: %s", source, message))
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "; ")
+}
+
+func (s *reconcileSummary) planText() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan := make([]string, len(s.plan))
+	copy(plan, s.plan)
+	sort.Strings(plan)
+
+	return strings.Join(plan, "\n")
+}
+
+func (s *reconcileSummary) providerNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DataReplaceInlineReconciler reconciles a DataReplaceInline object
+type DataReplaceInlineReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	DynamicClient dynamic.Interface
+	RESTMapper    meta.RESTMapper
+	Audit         *audit.Logger
+	Recorder      record.EventRecorder
+	Notifier      *notify.Notifier
+	Handlers      *handlers.Pool
+	Policy        *policy.Checker
+}
+
+//+kubebuilder:rbac:groups=datareplace.jaberchez.io,resources=datareplaceinlines,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=datareplace.jaberchez.io,resources=datareplaceinlines/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=datareplace.jaberchez.io,resources=datareplaceinlines/finalizers,verbs=update
+//+kubebuilder:rbac:groups=datareplace.jaberchez.io,resources=providerpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
+//+kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch;create;update;patch
+
+// Reconcile renders every manifest of a DataReplaceInline, applies the
+// results and reports the outcome in the status.
+func (r *DataReplaceInlineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	timer := prometheus.NewTimer(metrics.ReconcileDuration)
+	defer timer.ObserveDuration()
+
+	cr := &datareplacev1alpha1.DataReplaceInline{}
+
+	if err := r.Get(ctx, req.NamespacedName, cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	cr.Status.SyncAttempts++
+
+	if err := r.verifyManifests(ctx, cr); err != nil {
+		return r.updateStatusError(ctx, cr, err)
+	}
+
+	summary := newReconcileSummary()
+
+	// Manifests render and apply through a bounded worker pool with a
+	// per-item timeout, so large fan-outs complete in parallel instead of
+	// serially while keeping provider load and memory bounded.
+	type manifestResult struct {
+		kind     string
+		name     string
+		rendered string
+		values   []redact.SensitiveString
+	}
+
+	results := make([]manifestResult, len(cr.Spec.Manifests))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(renderWorkers)
+
+	for i, manifest := range cr.Spec.Manifests {
+		i, manifest := i, manifest
+
+		group.Go(func() error {
+			itemCtx, cancel := context.WithTimeout(groupCtx, manifestTimeout)
+			defer cancel()
+
+			rendered, values, err := r.ProcessManifest(itemCtx, cr, manifest, summary)
+			if err != nil {
+				return fmt.Errorf("unable to render manifest %d: %w", i, err)
+			}
+
+			kind, name, err := r.applyManifest(itemCtx, cr, rendered)
+			if err != nil {
+				return fmt.Errorf("unable to apply manifest %d: %w", i, err)
+			}
+
+			results[i] = manifestResult{kind: kind, name: name, rendered: rendered, values: values}
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return r.updateStatusError(ctx, cr, err)
+	}
+
+	var kinds, names []string
+	var dumps []string
+
+	// Redacted dumps feed both the status (spec.debug.renderedManifests) and
+	// the export ConfigMap (spec.debug.exportTo).
+	wantDumps := cr.Spec.Debug != nil && (cr.Spec.Debug.RenderedManifests || len(cr.Spec.Debug.ExportTo) > 0)
+
+	hasher := sha256.New()
+
+	for _, result := range results {
+		if wantDumps {
+			dumps = append(dumps, redact.Line(result.rendered, result.values))
+		}
+
+		summary.bytesRendered += len(result.rendered)
+		summary.applied++
+		hasher.Write([]byte(result.rendered))
+
+		kinds = append(kinds, result.kind)
+		names = append(names, result.name)
+	}
+
+	// Masking is done; wipe the resolved values so they don't linger in
+	// memory until the next garbage collection.
+	for _, result := range results {
+		for _, value := range result.values {
+			value.Zero()
+		}
+	}
+
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	// A changed checksum means a managed resource was rendered differently;
+	// propagate it to the configured workloads so their pods restart.
+	if hash != cr.Status.LastAppliedHash {
+		if err := r.bumpRolloutAnnotations(ctx, cr, hash); err != nil {
+			return r.updateStatusError(ctx, cr, err)
+		}
+	}
+
+	cr.Status.Ready = true
+	cr.Status.TargetKind = strings.Join(kinds, ",")
+	cr.Status.TargetName = strings.Join(names, ",")
+	cr.Status.LastSyncTime = &metav1.Time{Time: time.Now()}
+	cr.Status.Message = fmt.Sprintf("%d manifests applied", len(cr.Spec.Manifests))
+	if cr.Spec.Debug != nil && cr.Spec.Debug.RenderedManifests {
+		cr.Status.RenderedManifests = dumps
+	} else {
+		cr.Status.RenderedManifests = nil
+	}
+	cr.Status.ConsecutiveFailures = 0
+	cr.Status.LastAppliedHash = hash
+	cr.Status.Outputs = cr.Spec.Outputs
+
+	// Dynamic secrets carry a lease; surface the earliest expiry and come
+	// back shortly before it so rotated credentials are re-rendered.
+	cr.Status.LeaseExpiry = nil
+
+	if rotation := summary.rotationTime(); !rotation.IsZero() {
+		cr.Status.LeaseExpiry = &metav1.Time{Time: rotation}
+	}
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    degradedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "all manifests rendered and applied",
+	})
+
+	// Background credential renewal failures do not fail the sync (the
+	// handlers fall back to a fresh login), but they are surfaced so a
+	// degrading provider is visible before its token stops working.
+	renewalCondition := metav1.Condition{
+		Type:    renewalDegradedCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "credential renewal healthy",
+	}
+
+	if renewalErrors := summary.renewalText(); len(renewalErrors) > 0 {
+		renewalCondition.Status = metav1.ConditionTrue
+		renewalCondition.Reason = "RenewalFailed"
+		renewalCondition.Message = renewalErrors
+	}
+
+	meta.SetStatusCondition(&cr.Status.Conditions, renewalCondition)
+
+	if err := r.Status().Update(ctx, cr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Export is best effort: a broken debug ConfigMap must not fail an
+	// otherwise successful sync.
+	if cr.Spec.Debug != nil && len(cr.Spec.Debug.ExportTo) > 0 {
+		if err := r.exportDebugArtifacts(ctx, cr, dumps, summary, hash); err != nil {
+			logger.Error(err, "unable to export debug artifacts", "configMap", cr.Spec.Debug.ExportTo)
+		}
+	}
+
+	r.Recorder.Eventf(cr, corev1.EventTypeNormal, "Synced", "%d manifests applied", len(cr.Spec.Manifests))
+
+	logger.Info("reconcile summary",
+		"manifests", len(cr.Spec.Manifests),
+		"placeholders", summary.placeholders,
+		"providers", summary.providerNames(),
+		"cacheHits", summary.cacheHitCount(),
+		"bytesRendered", summary.bytesRendered,
+		"applied", summary.applied,
+		"applyVerb", "apply",
+	)
+
+	// A dynamic secret lease and spec.refreshInterval both schedule a
+	// comeback; the sooner one wins.
+	var requeue time.Duration
+
+	if rotation := summary.rotationTime(); !rotation.IsZero() {
+		requeue = time.Until(rotation) - leaseRequeueLeeway
+		if requeue < leaseRequeueFloor {
+			requeue = leaseRequeueFloor
+		}
+
+		logger.Info("requeueing before lease expiry", "leaseExpiry", rotation, "requeueAfter", requeue)
+	}
+
+	if cr.Spec.RefreshInterval != nil && cr.Spec.RefreshInterval.Duration > 0 {
+		interval := cr.Spec.RefreshInterval.Duration
+		if interval < leaseRequeueFloor {
+			interval = leaseRequeueFloor
+		}
+
+		if requeue == 0 || interval < requeue {
+			requeue = interval
+
+			logger.Info("requeueing for refresh interval", "requeueAfter", requeue)
+		}
+	}
+
+	if requeue > 0 {
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// updateStatusError records a failed reconcile in the status and returns the
+// error so the request is retried with backoff.
+func (r *DataReplaceInlineReconciler) updateStatusError(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, reconcileErr error) (ctrl.Result, error) {
+	cr.Status.Ready = false
+	cr.Status.Message = reconcileErr.Error()
+	cr.Status.ConsecutiveFailures++
+
+	reason := errclass.Classify(reconcileErr)
+
+	// Notify only on the transition into Degraded, not on every retry.
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, degradedCondition) {
+		r.Notifier.NotifyFailure(ctx, notify.Notification{
+			CRNamespace: cr.Namespace,
+			CRName:      cr.Name,
+			Reason:      reason,
+			Message:     reconcileErr.Error(),
+		})
+	}
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    degradedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: reconcileErr.Error(),
+	})
+
+	r.Recorder.Event(cr, corev1.EventTypeWarning, "SyncFailed", reconcileErr.Error())
+
+	if err := r.Status().Update(ctx, cr); err != nil {
+		log.FromContext(ctx).Error(err, "unable to update status")
+	}
+
+	return ctrl.Result{}, reconcileErr
+}
+
+// ProcessManifest replaces every placeholder in a manifest and returns the
+// rendered result together with the resolved values, so callers can mask
+// them in any output derived from the rendered manifest.
+func (r *DataReplaceInlineReconciler) ProcessManifest(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, manifest string, summary *reconcileSummary) (string, []redact.SensitiveString, error) {
+	timer := prometheus.NewTimer(metrics.RenderDuration)
+	defer timer.ObserveDuration()
+
+	ctx, span := tracing.Tracer().Start(ctx, "ProcessManifest")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("datareplace.cr.namespace", cr.Namespace),
+		attribute.String("datareplace.cr.name", cr.Name),
+	)
+
+	var values []redact.SensitiveString
+
+	// resolved deduplicates identical placeholders within this manifest so
+	// each unique reference triggers a single provider call.
+	resolved := map[string]redact.SensitiveString{}
+
+	// Rendered lines are streamed into a single builder sized to the input,
+	// so large manifests are not copied line by line into a growing string.
+	var rendered strings.Builder
+	rendered.Grow(len(manifest))
+
+	scanner := bufio.NewScanner(strings.NewReader(manifest))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	first := true
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line, lineValues, err := r.processLine(ctx, cr, scanner.Text(), summary, resolved)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "render failed")
+
+			// The error carries the placeholder text and line number but
+			// never line content, which may contain resolved values.
+			return "", nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if !first {
+			rendered.WriteByte('\n')
+		}
+
+		first = false
+		rendered.WriteString(line)
+		values = append(values, lineValues...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return "", nil, errclass.Wrap(errclass.InvalidManifest,
+				fmt.Errorf("manifest contains a line longer than %d bytes", maxLineSize))
+		}
+
+		return "", nil, fmt.Errorf("unable to scan manifest: %w", err)
+	}
+
+	return rendered.String(), values, nil
+}
+
+// processLine replaces every placeholder found in a single manifest line and
+// returns the resolved values alongside the rendered line.
+// Resolved values arrive as redact.SensitiveString; the plaintext is only
+// revealed at the substitution into the line.
+func (r *DataReplaceInlineReconciler) processLine(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, line string, summary *reconcileSummary, resolved map[string]redact.SensitiveString) (string, []redact.SensitiveString, error) {
+	var values []redact.SensitiveString
+
+	// Cross-resource references resolve first: they read declared,
+	// non-sensitive outputs and may themselves be part of placeholder data.
+	for _, ref := range placeholder.FindReferences(line) {
+		other := &datareplacev1alpha1.DataReplaceInline{}
+
+		if err := r.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: ref.Name}, other); err != nil {
+			return "", nil, fmt.Errorf("unable to resolve reference %s: %w", ref.Raw, err)
+		}
+
+		value, ok := other.Status.Outputs[ref.Output]
+		if !ok {
+			// Returning an error requeues with backoff until the dependency
+			// publishes the output.
+			return "", nil, fmt.Errorf("unable to resolve reference %s: output %s not published by %s yet",
+				ref.Raw, ref.Output, ref.Name)
+		}
+
+		line = strings.Replace(line, ref.Raw, value, 1)
+	}
+
+	for _, match := range placeholder.Find(line) {
+		raw := match.Raw
+		provider := match.Provider
+		secretName := match.Secret
+		data := match.Data
+
+		summary.recordPlaceholder(match)
+
+		// Debug log of the match only: placeholder fields are safe, resolved
+		// values are not.
+		log.FromContext(ctx).V(1).Info("placeholder matched",
+			"provider", provider, "secret", secretName, "data", data)
+
+		if value, ok := resolved[raw]; ok {
+			summary.recordCacheHit()
+			values = append(values, value)
+			line = strings.Replace(line, raw, value.Reveal(), 1)
+			continue
+		}
+
+		if err := r.Policy.Allowed(ctx, cr.Namespace, provider, secretName); err != nil {
+			metrics.ResolutionErrors.WithLabelValues(provider, secretName, cr.Namespace, cr.Name, "policy").Inc()
+			return "", nil, fmt.Errorf("unable to resolve placeholder %s: %w", raw, err)
+		}
+
+		// Error messages reference the placeholder only: by the time an
+		// error occurs the line may already contain resolved values from
+		// earlier placeholders and must never be echoed.
+		handler, pooled, err := r.Handlers.Get(ctx, cr.Namespace, provider, secretName)
+		if err != nil {
+			metrics.ResolutionErrors.WithLabelValues(provider, secretName, cr.Namespace, cr.Name, "config").Inc()
+			return "", nil, fmt.Errorf("unable to resolve placeholder %s: %w", raw, err)
+		}
+
+		if pooled {
+			summary.recordCacheHit()
+		}
+
+		callCtx, callSpan := tracing.Tracer().Start(ctx, "Handler.GetData")
+		callSpan.SetAttributes(
+			attribute.String("datareplace.provider", provider),
+			attribute.String("datareplace.provider.secret", secretName),
+		)
+
+		callStart := time.Now()
+		value, err := handler.GetData(callCtx, data)
+		metrics.ProviderCallDuration.WithLabelValues(provider).Observe(time.Since(callStart).Seconds())
+
+		record := audit.Record{
+			CRNamespace: cr.Namespace,
+			CRName:      cr.Name,
+			Placeholder: raw,
+			Provider:    provider,
+			Secret:      secretName,
+			Data:        data,
+			Result:      audit.ResultSuccess,
+		}
+
+		if err != nil {
+			record.Result = audit.ResultError
+			record.Error = err.Error()
+		}
+
+		r.Audit.Log(ctx, record)
+
+		if err != nil {
+			callSpan.RecordError(err)
+			callSpan.SetStatus(codes.Error, "provider call failed")
+		}
+
+		callSpan.End()
+
+		if err != nil {
+			metrics.ProviderCallErrors.WithLabelValues(provider).Inc()
+			metrics.ResolutionErrors.WithLabelValues(provider, secretName, cr.Namespace, cr.Name, "resolve").Inc()
+			return "", nil, fmt.Errorf("unable to resolve placeholder %s: %w", raw, err)
+		}
+
+		if holder, ok := handler.(handlers.LeaseHolder); ok {
+			if rotation, leased := holder.NextRotation(); leased {
+				summary.recordRotation(rotation)
+			}
+		}
+
+		if reporter, ok := handler.(handlers.RenewalReporter); ok {
+			if renewErr := reporter.RenewalError(); renewErr != nil {
+				summary.recordRenewalError(provider, secretName, renewErr)
+			}
+		}
+
+		resolved[raw] = value
+		values = append(values, value)
+		line = strings.Replace(line, raw, value.Reveal(), 1)
+	}
+
+	return line, values, nil
+}
+
+// applyManifest applies a rendered manifest with server-side apply and
+// returns the kind and name of the applied resource.
+func (r *DataReplaceInlineReconciler) applyManifest(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, manifest string) (string, string, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ApplyManifest")
+	defer span.End()
+
+	obj := &unstructured.Unstructured{}
+
+	if err := yaml.Unmarshal([]byte(manifest), obj); err != nil {
+		return "", "", errclass.Wrap(errclass.InvalidManifest, fmt.Errorf("unable to decode rendered manifest: %w", err))
+	}
+
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := r.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to map %s: %w", gvk, err)
+	}
+
+	resource := r.DynamicClient.Resource(mapping.Resource)
+
+	var dr dynamic.ResourceInterface = resource
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if len(obj.GetNamespace()) == 0 {
+			obj.SetNamespace(cr.Namespace)
+		}
+
+		dr = resource.Namespace(obj.GetNamespace())
+	}
+
+	if err := r.checkApplyPermission(ctx, mapping.Resource, obj.GetNamespace()); err != nil {
+		return "", "", err
+	}
+
+	payload, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to encode rendered manifest: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.String("datareplace.target.kind", gvk.Kind),
+		attribute.String("datareplace.target.name", obj.GetName()),
+	)
+
+	applied, err := dr.Patch(ctx, obj.GetName(), types.ApplyPatchType, payload, metav1.PatchOptions{FieldManager: fieldManager})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "apply failed")
+		return "", "", fmt.Errorf("unable to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	// An event on the target itself tells the owners of that resource where
+	// the change came from.
+	r.Recorder.Eventf(applied, corev1.EventTypeNormal, "Updated",
+		"Updated by DataReplaceInline %s/%s", cr.Namespace, cr.Name)
+
+	metrics.AppliedResources.Inc()
+
+	return gvk.Kind, obj.GetName(), nil
+}
+
+// verifyManifests checks the cosign signature of every manifest against the
+// configured public key before anything is rendered or applied. Manifests of
+// resources without spec.verification are accepted as-is.
+func (r *DataReplaceInlineReconciler) verifyManifests(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline) error {
+	verification := cr.Spec.Verification
+	if verification == nil {
+		return nil
+	}
+
+	if len(verification.Signatures) != len(cr.Spec.Manifests) {
+		return errclass.Wrap(errclass.SignatureInvalid,
+			fmt.Errorf("%d signatures provided for %d manifests", len(verification.Signatures), len(cr.Spec.Manifests)))
+	}
+
+	secret := &corev1.Secret{}
+
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: verification.SecretName}, secret); err != nil {
+		return fmt.Errorf("unable to get verification secret %s/%s: %w", cr.Namespace, verification.SecretName, err)
+	}
+
+	publicKey, ok := secret.Data["cosign.pub"]
+	if !ok {
+		return fmt.Errorf("verification secret %s is missing the cosign.pub key", verification.SecretName)
+	}
+
+	for i, manifest := range cr.Spec.Manifests {
+		if err := verify.Signature(publicKey, manifest, verification.Signatures[i]); err != nil {
+			return errclass.Wrap(errclass.SignatureInvalid,
+				fmt.Errorf("manifest %d failed signature verification: %w", i, err))
+		}
+	}
+
+	return nil
+}
+
+// exportDebugArtifacts writes the redacted rendered manifests, the
+// placeholder plan and resolution provenance into the ConfigMap named by
+// spec.debug.exportTo. Everything written is already masked.
+func (r *DataReplaceInlineReconciler) exportDebugArtifacts(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, dumps []string, summary *reconcileSummary, hash string) error {
+	exported := &corev1.ConfigMap{}
+	exported.Namespace = cr.Namespace
+	exported.Name = cr.Spec.Debug.ExportTo
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, exported, func() error {
+		exported.Data = map[string]string{
+			"rendered.yaml": strings.Join(dumps, "\n---\n"),
+			"plan.txt":      summary.planText(),
+			"provenance.txt": fmt.Sprintf("syncedAt=%s\nhash=%s\nmanifests=%d\nplaceholders=%d\nproviders=%s\n",
+				time.Now().Format(time.RFC3339), hash, len(cr.Spec.Manifests), summary.placeholders,
+				strings.Join(summary.providerNames(), ",")),
+		}
+
+		return controllerutil.SetControllerReference(cr, exported, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write debug configmap %s/%s: %w", cr.Namespace, cr.Spec.Debug.ExportTo, err)
+	}
+
+	return nil
+}
+
+// bumpRolloutAnnotations sets the checksum annotation on the pod template of
+// every rollout target, restarting their pods with the rotated data.
+func (r *DataReplaceInlineReconciler) bumpRolloutAnnotations(ctx context.Context, cr *datareplacev1alpha1.DataReplaceInline, hash string) error {
+	if cr.Spec.Rollout == nil {
+		return nil
+	}
+
+	annotation := cr.Spec.Rollout.Annotation
+	if len(annotation) == 0 {
+		annotation = defaultRolloutAnnotation
+	}
+
+	for _, target := range cr.Spec.Rollout.Targets {
+		namespace := target.Namespace
+		if len(namespace) == 0 {
+			namespace = cr.Namespace
+		}
+
+		workload := &unstructured.Unstructured{}
+		workload.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: target.Kind})
+		workload.SetNamespace(namespace)
+		workload.SetName(target.Name)
+
+		payload := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, annotation, hash)
+
+		if err := r.Patch(ctx, workload, client.RawPatch(types.MergePatchType, []byte(payload))); err != nil {
+			return fmt.Errorf("unable to bump rollout annotation on %s %s/%s: %w", target.Kind, namespace, target.Name, err)
+		}
+
+		r.Recorder.Eventf(cr, corev1.EventTypeNormal, "RolloutTriggered",
+			"Bumped %s on %s %s/%s", annotation, target.Kind, namespace, target.Name)
+	}
+
+	return nil
+}
+
+// checkApplyPermission verifies through a SelfSubjectAccessReview that the
+// operator service account may patch the target resource before the apply is
+// attempted. A denial surfaces as an RBACDenied error naming the missing
+// verb and resource, which is far more actionable in the status than the raw
+// Forbidden string returned by the dynamic client.
+func (r *DataReplaceInlineReconciler) checkApplyPermission(ctx context.Context, gvr schema.GroupVersionResource, namespace string) error {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:      "patch",
+				Group:     gvr.Group,
+				Version:   gvr.Version,
+				Resource:  gvr.Resource,
+				Namespace: namespace,
+			},
+		},
+	}
+
+	if err := r.Create(ctx, review); err != nil {
+		return fmt.Errorf("unable to check permissions for %s: %w", gvr.Resource, err)
+	}
+
+	if !review.Status.Allowed {
+		resource := gvr.Resource
+		if len(gvr.Group) > 0 {
+			resource += "." + gvr.Group
+		}
+
+		message := fmt.Sprintf("operator is not allowed to patch %s", resource)
+		if len(namespace) > 0 {
+			message += " in namespace " + namespace
+		}
+
+		message += "; grant the verb patch on this resource to the operator service account"
+
+		return errclass.Wrap(errclass.RBACDenied, errors.New(message))
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DataReplaceInlineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&datareplacev1alpha1.DataReplaceInline{}).
+		Complete(r)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure that exec-entrypoint and run can make use of them.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/controllers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/audit"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/health"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/notify"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/policy"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/tracing"
+	driwebhook "github.com/jaberchez/operator-data-replace-inline/pkg/webhook"
+)
+
+var (
+	scheme   = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(datareplacev1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	var metricsAddr string
+	var enableLeaderElection bool
+	var probeAddr string
+	var auditSinkURL string
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&auditSinkURL, "audit-sink-url", "",
+		"Optional URL audit records of external secret reads are POSTed to as JSON.")
+	var failureWebhookURL string
+	flag.StringVar(&failureWebhookURL, "failure-webhook-url", "",
+		"Optional webhook URL notified when a resource transitions to Degraded.")
+	var pprofAddr string
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "",
+		"The address the pprof endpoint binds to. Disabled when empty.")
+	var providerRateLimits string
+	flag.StringVar(&providerRateLimits, "provider-rate-limits", "",
+		"Per-provider client-side rate limits as <provider>=<calls-per-second> pairs, e.g. \"vault=10,git=2\".")
+	var repoCacheBudget int64
+	flag.Int64Var(&repoCacheBudget, "repo-cache-budget-bytes", 0,
+		"Disk budget for cached git clones in bytes. Zero keeps the default of 1GiB.")
+	var forbidInsecureTLS bool
+	flag.BoolVar(&forbidInsecureTLS, "forbid-insecure-tls", false,
+		"Reject provider configurations that disable TLS certificate verification.")
+	var enableFIPSMode bool
+	flag.BoolVar(&enableFIPSMode, "fips-mode", false,
+		"Restrict provider connections to TLS 1.2+ with FIPS-approved cipher suites and reject insecure TLS. "+
+			"Covers the HTTP(S)-based providers, git clones and fetches, Consul and the AWS SDK; the gcpsm provider speaks gRPC and is disabled.")
+	var noPlaintextOnDisk bool
+	flag.BoolVar(&noPlaintextOnDisk, "no-plaintext-on-disk", false,
+		"Keep fetched provider content in memory only; disables the on-disk git clone cache.")
+	var enableInjectWebhook bool
+	flag.BoolVar(&enableInjectWebhook, "enable-inject-webhook", false,
+		"Serve a mutating webhook on /inject-placeholders resolving placeholders in resources annotated with datareplaceinline/inject.")
+	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	// Logs are structured JSON by default; --zap-devel switches to the
+	// human-friendly console encoder. The atomic level lets SIGUSR1 bump
+	// verbosity to debug at runtime and SIGUSR2 restore it, without a
+	// restart.
+	logLevel := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	opts := zap.Options{
+		Development: false,
+		Level:       &logLevel,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	levelSignals := make(chan os.Signal, 1)
+	signal.Notify(levelSignals, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range levelSignals {
+			switch sig {
+			case syscall.SIGUSR1:
+				logLevel.SetLevel(zapcore.DebugLevel)
+			case syscall.SIGUSR2:
+				logLevel.SetLevel(zapcore.InfoLevel)
+			}
+
+			setupLog.Info("log level changed", "level", logLevel.Level().String())
+		}
+	}()
+
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to shut down tracing")
+		}
+	}()
+
+	if err := handlers.SetRateLimits(providerRateLimits); err != nil {
+		setupLog.Error(err, "invalid provider rate limits")
+		os.Exit(1)
+	}
+
+	handlers.SetRepoCacheBudget(repoCacheBudget)
+	handlers.SetForbidInsecureTLS(forbidInsecureTLS)
+	handlers.SetFIPSMode(enableFIPSMode)
+	handlers.SetNoPlaintextOnDisk(noPlaintextOnDisk)
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress: probeAddr,
+		PprofBindAddress:       pprofAddr,
+		LeaderElection:         enableLeaderElection,
+		LeaderElectionID:       "6b8e7f42.jaberchez.io",
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create dynamic client")
+		os.Exit(1)
+	}
+
+	handlerPool := handlers.NewPool(mgr.GetClient())
+	policyChecker := policy.NewChecker(mgr.GetClient())
+
+	if err = (&controllers.DataReplaceInlineReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		DynamicClient: dynamicClient,
+		RESTMapper:    mgr.GetRESTMapper(),
+		Audit:         audit.NewLogger(auditSinkURL),
+		Recorder:      mgr.GetEventRecorderFor("operator-data-replace-inline"),
+		Notifier:      notify.NewNotifier(failureWebhookURL),
+		Handlers:      handlerPool,
+		Policy:        policyChecker,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DataReplaceInline")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.AnnotatedConfigMapReconciler{
+		Client:   mgr.GetClient(),
+		Handlers: handlerPool,
+		Policy:   policyChecker,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AnnotatedConfigMap")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.AnnotatedSecretReconciler{
+		Client:   mgr.GetClient(),
+		Handlers: handlerPool,
+		Policy:   policyChecker,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AnnotatedSecret")
+		os.Exit(1)
+	}
+
+	if enableInjectWebhook {
+		mgr.GetWebhookServer().Register("/inject-placeholders", &webhook.Admission{
+			Handler: &driwebhook.PlaceholderInjector{
+				Handlers: handlerPool,
+				Policy:   policyChecker,
+			},
+		})
+	}
+
+	prober := &health.Prober{Client: mgr.GetClient(), Handlers: handlerPool}
+	if err := mgr.Add(prober); err != nil {
+		setupLog.Error(err, "unable to add provider prober")
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("providers", prober.Check); err != nil {
+		setupLog.Error(err, "unable to set up provider ready check")
+		os.Exit(1)
+	}
+
+	setupLog.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running manager")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,109 @@
+// Package audit emits structured records of external secret reads.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Record describes a single external secret read performed on behalf of a
+// custom resource.
+type Record struct {
+	Time        time.Time `json:"time"`
+	CRNamespace string    `json:"crNamespace"`
+	CRName      string    `json:"crName"`
+	Placeholder string    `json:"placeholder"`
+	Provider    string    `json:"provider"`
+	Secret      string    `json:"secret"`
+	Data        string    `json:"data"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+	Caller      string    `json:"caller"`
+}
+
+// Results recorded for a secret read.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Logger writes audit records to the operator log and, when a sink URL is
+// configured, ships them as JSON to a webhook or syslog gateway.
+type Logger struct {
+	sinkURL string
+	caller  string
+	client  *http.Client
+	log     logr.Logger
+}
+
+// NewLogger builds a Logger. sinkURL may be empty, in which case records are
+// only written to the operator log.
+func NewLogger(sinkURL string) *Logger {
+	caller, err := os.Hostname()
+	if err != nil {
+		caller = "operator-data-replace-inline"
+	}
+
+	return &Logger{
+		sinkURL: sinkURL,
+		caller:  caller,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		log:     ctrl.Log.WithName("audit"),
+	}
+}
+
+// Log records one secret read. Sink delivery failures are logged but never
+// fail the reconcile that triggered the read.
+func (l *Logger) Log(ctx context.Context, record Record) {
+	record.Time = time.Now()
+	record.Caller = l.caller
+
+	l.log.Info("secret read",
+		"crNamespace", record.CRNamespace,
+		"crName", record.CRName,
+		"placeholder", record.Placeholder,
+		"provider", record.Provider,
+		"secret", record.Secret,
+		"data", record.Data,
+		"result", record.Result,
+		"error", record.Error,
+		"caller", record.Caller,
+	)
+
+	if len(l.sinkURL) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(record)
+	if err != nil {
+		l.log.Error(err, "unable to encode audit record")
+		return
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, l.sinkURL, bytes.NewReader(payload))
+	if err != nil {
+		l.log.Error(err, "unable to build audit sink request")
+		return
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := l.client.Do(request)
+	if err != nil {
+		l.log.Error(err, "unable to ship audit record")
+		return
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		l.log.Info("audit sink rejected record", "status", response.StatusCode)
+	}
+}
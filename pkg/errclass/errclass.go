@@ -0,0 +1,62 @@
+// Package errclass tags reconcile errors with machine-readable reasons so
+// automation can react differently to each class of failure.
+package errclass
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Reasons surfaced in the Degraded condition.
+const (
+	ProviderAuthFailed = "ProviderAuthFailed"
+	ProviderForbidden  = "ProviderForbidden"
+	KeyNotFound        = "KeyNotFound"
+	InvalidManifest    = "InvalidManifest"
+	SignatureInvalid   = "SignatureInvalid"
+	RBACDenied         = "RBACDenied"
+	Conflict           = "Conflict"
+	Unknown            = "Unknown"
+)
+
+// classified carries a reason along an error chain.
+type classified struct {
+	reason string
+	err    error
+}
+
+func (c *classified) Error() string {
+	return c.err.Error()
+}
+
+func (c *classified) Unwrap() error {
+	return c.err
+}
+
+// Wrap tags err with a reason. The reason survives further wrapping with
+// fmt.Errorf and %w.
+func Wrap(reason string, err error) error {
+	return &classified{reason: reason, err: err}
+}
+
+// Classify returns the machine-readable reason for err. Errors tagged with
+// Wrap keep their reason; Kubernetes API errors are mapped from their status,
+// everything else is Unknown.
+func Classify(err error) string {
+	var c *classified
+	if errors.As(err, &c) {
+		return c.reason
+	}
+
+	switch {
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return RBACDenied
+	case apierrors.IsConflict(err):
+		return Conflict
+	case apierrors.IsNotFound(err):
+		return KeyNotFound
+	}
+
+	return Unknown
+}
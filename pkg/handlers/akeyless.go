@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the akeyless provider configuration.
+const (
+	akeylessKeyAccessID  = "accessID"
+	akeylessKeyAccessKey = "accessKey"
+	akeylessKeyJWT       = "jwt"
+	akeylessKeyAPIURL    = "apiURL"
+)
+
+// akeylessDefaultAPIURL is the hosted Akeyless gateway API.
+const akeylessDefaultAPIURL = "https://api.akeyless.io"
+
+// akeylessTokenTTL is how long a fetched Akeyless token is reused before
+// re-authenticating.
+const akeylessTokenTTL = 30 * time.Minute
+
+func init() {
+	Register(AkeylessProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewAkeylessHandler(secret)
+	})
+}
+
+// AkeylessHandler resolves placeholders of the form
+// {{ akeyless:<secret>:<path> }} or {{ akeyless:<secret>:<path>#<key> }}
+// against Akeyless, following the same path-and-key grammar as the vault
+// provider. Authentication uses an access id with either an access key or a
+// JWT.
+type AkeylessHandler struct {
+	client    *nethttp.Client
+	apiURL    string
+	accessID  string
+	accessKey string
+	jwt       string
+
+	tokenMu      sync.Mutex
+	token        string
+	tokenFetched time.Time
+}
+
+// NewAkeylessHandler builds an AkeylessHandler from a provider Secret.
+func NewAkeylessHandler(secret *corev1.Secret) (*AkeylessHandler, error) {
+	h := &AkeylessHandler{
+		apiURL:    string(secret.Data[akeylessKeyAPIURL]),
+		accessID:  string(secret.Data[akeylessKeyAccessID]),
+		accessKey: string(secret.Data[akeylessKeyAccessKey]),
+		jwt:       string(secret.Data[akeylessKeyJWT]),
+	}
+
+	if len(h.accessID) == 0 {
+		return nil, fmt.Errorf("akeyless provider secret %s is missing the accessID key", secret.Name)
+	}
+
+	if len(h.accessKey) == 0 && len(h.jwt) == 0 {
+		return nil, fmt.Errorf("akeyless provider secret %s needs either the accessKey or jwt key", secret.Name)
+	}
+
+	if len(h.apiURL) == 0 {
+		h.apiURL = akeylessDefaultAPIURL
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	h.client = client
+
+	return h, nil
+}
+
+// authenticate fetches an Akeyless token, reusing a cached one while fresh.
+func (h *AkeylessHandler) authenticate(ctx context.Context) (string, error) {
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	if len(h.token) > 0 && time.Since(h.tokenFetched) < akeylessTokenTTL {
+		return h.token, nil
+	}
+
+	request := map[string]string{"access-id": h.accessID}
+
+	if len(h.jwt) > 0 {
+		request["access-type"] = "jwt"
+		request["jwt"] = h.jwt
+	} else {
+		request["access-type"] = "access_key"
+		request["access-key"] = h.accessKey
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	status, data, err := restRequest(ctx, h.client, nethttp.MethodPost, h.apiURL+"/auth", headers, body)
+	if err != nil {
+		return "", err
+	}
+
+	if status >= 400 {
+		return "", errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("akeyless authentication failed with status %d", status))
+	}
+
+	var response struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("unable to parse akeyless auth response: %w", err)
+	}
+
+	if len(response.Token) == 0 {
+		return "", errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("akeyless authentication returned no token"))
+	}
+
+	h.token = response.Token
+	h.tokenFetched = time.Now()
+
+	return h.token, nil
+}
+
+// Check verifies authentication against Akeyless succeeds.
+func (h *AkeylessHandler) Check(ctx context.Context) error {
+	h.tokenMu.Lock()
+	h.token = ""
+	h.tokenMu.Unlock()
+
+	_, err := h.authenticate(ctx)
+
+	return err
+}
+
+// GetData reads the Akeyless secret at <path>, optionally extracting the
+// field <key> from a JSON value with the <path>#<key> form.
+func (h *AkeylessHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	path := data
+	key := ""
+
+	if before, after, found := strings.Cut(data, "#"); found {
+		path = before
+		key = after
+	}
+
+	token, err := h.authenticate(ctx)
+	if err != nil {
+		return none, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"names": []string{path},
+		"token": token,
+	})
+	if err != nil {
+		return none, err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	status, response, err := restRequest(ctx, h.client, nethttp.MethodPost, h.apiURL+"/get-secret-value", headers, body)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read akeyless secret %s: status %d", path, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("akeyless secret %s not found", path))
+	case status >= 400:
+		return none, fmt.Errorf("unable to read akeyless secret %s: status %d", path, status)
+	}
+
+	var values map[string]interface{}
+
+	if err := json.Unmarshal(response, &values); err != nil {
+		return none, fmt.Errorf("unable to parse akeyless response for %s: %w", path, err)
+	}
+
+	value, ok := values[path]
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("akeyless secret %s not found", path))
+	}
+
+	if len(key) == 0 {
+		return redact.Sensitive(fmt.Sprintf("%v", value)), nil
+	}
+
+	// A key suffix indexes into a JSON-valued secret, like the vault
+	// provider's <path>#<key>.
+	fields, ok := value.(map[string]interface{})
+
+	if !ok {
+		text, isString := value.(string)
+		if isString && json.Unmarshal([]byte(text), &fields) == nil {
+			ok = true
+		}
+	}
+
+	if !ok {
+		return none, fmt.Errorf("akeyless secret %s does not hold a JSON object, cannot extract key %s", path, key)
+	}
+
+	field, found := fields[key]
+	if !found {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found in akeyless secret %s", key, path))
+	}
+
+	return redact.Sensitive(fmt.Sprintf("%v", field)), nil
+}
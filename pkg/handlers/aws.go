@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	nethttp "net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Secret keys shared by the AWS providers.
+const (
+	awsKeyRegion          = "region"
+	awsKeyAccessKeyID     = "accessKeyID"
+	awsKeySecretAccessKey = "secretAccessKey"
+	awsKeySessionToken    = "sessionToken"
+)
+
+// awsConfigFields are the credential keys accepted by every AWS provider.
+func awsConfigFields() []ConfigField {
+	return []ConfigField{
+		{Name: awsKeyAccessKeyID, Comment: "static access key; omit to use the SDK default chain (IRSA)"},
+		{Name: awsKeySecretAccessKey, Comment: "static secret key; omit to use the SDK default chain (IRSA)"},
+		{Name: awsKeySessionToken, Comment: "session token for temporary static credentials"},
+	}
+}
+
+// newAWSSession builds an AWS session from the shared keys of a provider
+// Secret. When the Secret carries no static credentials, the SDK default
+// chain is used, so workload identity (IRSA) works without keys in the
+// Secret.
+func newAWSSession(secret *corev1.Secret, config *aws.Config) (*session.Session, error) {
+	region := string(secret.Data[awsKeyRegion])
+
+	if len(region) == 0 {
+		return nil, fmt.Errorf("provider secret %s is missing the region key", secret.Name)
+	}
+
+	config = config.WithRegion(region)
+
+	accessKeyID := string(secret.Data[awsKeyAccessKeyID])
+	secretAccessKey := string(secret.Data[awsKeySecretAccessKey])
+
+	if len(accessKeyID) > 0 || len(secretAccessKey) > 0 {
+		sessionToken := string(secret.Data[awsKeySessionToken])
+		config = config.WithCredentials(credentials.NewStaticCredentials(accessKeyID, secretAccessKey, sessionToken))
+	}
+
+	// The SDK default transport is unrestricted; under fips mode it is
+	// replaced so AWS calls honor the same TLS floor as every other
+	// provider.
+	if fipsMode {
+		config = config.WithHTTPClient(&nethttp.Client{
+			Transport: &nethttp.Transport{
+				TLSClientConfig: fipsTLSConfig(),
+				Proxy:           nethttp.ProxyFromEnvironment,
+			},
+		})
+	}
+
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create aws session: %w", err)
+	}
+
+	return sess, nil
+}
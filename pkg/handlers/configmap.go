@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the configmap provider configuration.
+const (
+	configmapKeyName      = "name"
+	configmapKeyNamespace = "namespace"
+)
+
+func init() {
+	Register(ConfigMapProvider, func(c client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewConfigMapHandler(c, secret)
+	})
+}
+
+// ConfigMapHandler resolves placeholders of the form
+// {{ configmap:<secret>:<key> }} against a referenced in-cluster ConfigMap,
+// so plain configuration does not have to be smuggled through Secrets just to
+// become a data source.
+type ConfigMapHandler struct {
+	client    client.Client
+	name      string
+	namespace string
+}
+
+// NewConfigMapHandler builds a ConfigMapHandler from a provider Secret. The
+// ConfigMap namespace defaults to the namespace of the provider Secret.
+func NewConfigMapHandler(c client.Client, secret *corev1.Secret) (*ConfigMapHandler, error) {
+	if c == nil {
+		return nil, fmt.Errorf("the configmap provider requires a cluster connection")
+	}
+
+	name := string(secret.Data[configmapKeyName])
+
+	if len(name) == 0 {
+		return nil, fmt.Errorf("configmap provider secret %s is missing the name key", secret.Name)
+	}
+
+	namespace := string(secret.Data[configmapKeyNamespace])
+
+	if len(namespace) == 0 {
+		namespace = secret.Namespace
+	}
+
+	return &ConfigMapHandler{client: c, name: name, namespace: namespace}, nil
+}
+
+// GetData reads the key named data from the referenced ConfigMap.
+func (h *ConfigMapHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	configMap := &corev1.ConfigMap{}
+
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: h.namespace, Name: h.name}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("configmap %s/%s not found", h.namespace, h.name))
+		}
+
+		return none, fmt.Errorf("unable to get configmap %s/%s: %w", h.namespace, h.name, err)
+	}
+
+	value, ok := configMap.Data[data]
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found in configmap %s/%s", data, h.namespace, h.name))
+	}
+
+	return redact.Sensitive(value), nil
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the conjur provider configuration.
+const (
+	conjurKeyApplianceURL = "applianceURL"
+	conjurKeyAccount      = "account"
+	conjurKeyLogin        = "login"
+	conjurKeyAPIKey       = "apiKey"
+	conjurKeyJWTServiceID = "jwtServiceID"
+	conjurKeyJWT          = "jwt"
+)
+
+// conjurTokenTTL is how long a fetched Conjur access token is reused before
+// re-authenticating; Conjur tokens are valid for eight minutes.
+const conjurTokenTTL = 5 * time.Minute
+
+func init() {
+	Register(ConjurProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewConjurHandler(secret)
+	})
+}
+
+// ConjurHandler resolves placeholders of the form
+// {{ conjur:<secret>:<path/to/variable> }} against a CyberArk Conjur
+// appliance, authenticating with either a login and API key or a JWT.
+type ConjurHandler struct {
+	client       *nethttp.Client
+	applianceURL string
+	account      string
+	login        string
+	apiKey       string
+	jwtServiceID string
+	jwt          string
+
+	tokenMu      sync.Mutex
+	token        string
+	tokenFetched time.Time
+}
+
+// NewConjurHandler builds a ConjurHandler from a provider Secret.
+func NewConjurHandler(secret *corev1.Secret) (*ConjurHandler, error) {
+	h := &ConjurHandler{
+		applianceURL: string(secret.Data[conjurKeyApplianceURL]),
+		account:      string(secret.Data[conjurKeyAccount]),
+		login:        string(secret.Data[conjurKeyLogin]),
+		apiKey:       string(secret.Data[conjurKeyAPIKey]),
+		jwtServiceID: string(secret.Data[conjurKeyJWTServiceID]),
+		jwt:          string(secret.Data[conjurKeyJWT]),
+	}
+
+	if len(h.applianceURL) == 0 {
+		return nil, fmt.Errorf("conjur provider secret %s is missing the applianceURL key", secret.Name)
+	}
+
+	if len(h.account) == 0 {
+		return nil, fmt.Errorf("conjur provider secret %s is missing the account key", secret.Name)
+	}
+
+	if len(h.apiKey) == 0 && len(h.jwt) == 0 {
+		return nil, fmt.Errorf("conjur provider secret %s needs either login and apiKey or jwtServiceID and jwt", secret.Name)
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	h.client = client
+
+	return h, nil
+}
+
+// authenticate fetches a short-lived Conjur access token, reusing a cached
+// one while it is still fresh.
+func (h *ConjurHandler) authenticate(ctx context.Context) (string, error) {
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	if len(h.token) > 0 && time.Since(h.tokenFetched) < conjurTokenTTL {
+		return h.token, nil
+	}
+
+	var authURL string
+	var body []byte
+
+	if len(h.jwt) > 0 {
+		authURL = fmt.Sprintf("%s/authn-jwt/%s/%s/authenticate", h.applianceURL, url.PathEscape(h.jwtServiceID), url.PathEscape(h.account))
+		body = []byte("jwt=" + url.QueryEscape(h.jwt))
+	} else {
+		authURL = fmt.Sprintf("%s/authn/%s/%s/authenticate", h.applianceURL, url.PathEscape(h.account), url.PathEscape(h.login))
+		body = []byte(h.apiKey)
+	}
+
+	headers := map[string]string{"Accept-Encoding": "base64"}
+
+	if len(h.jwt) > 0 {
+		headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+
+	status, data, err := restRequest(ctx, h.client, nethttp.MethodPost, authURL, headers, body)
+	if err != nil {
+		return "", err
+	}
+
+	if status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden {
+		return "", errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("conjur authentication failed with status %d", status))
+	}
+
+	if status >= 400 {
+		return "", fmt.Errorf("conjur authentication returned status %d", status)
+	}
+
+	// With Accept-Encoding base64 the token arrives ready to embed in the
+	// authorization header.
+	h.token = string(data)
+	h.tokenFetched = time.Now()
+
+	return h.token, nil
+}
+
+// Check verifies authentication against the Conjur appliance succeeds.
+func (h *ConjurHandler) Check(ctx context.Context) error {
+	h.tokenMu.Lock()
+	h.token = ""
+	h.tokenMu.Unlock()
+
+	_, err := h.authenticate(ctx)
+
+	return err
+}
+
+// GetData reads the Conjur variable at the path data.
+func (h *ConjurHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	token, err := h.authenticate(ctx)
+	if err != nil {
+		return none, err
+	}
+
+	variableURL := fmt.Sprintf("%s/secrets/%s/variable/%s", h.applianceURL, url.PathEscape(h.account), url.PathEscape(data))
+	headers := map[string]string{
+		"Authorization": fmt.Sprintf("Token token=%q", token),
+	}
+
+	status, value, err := restRequest(ctx, h.client, nethttp.MethodGet, variableURL, headers, nil)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read conjur variable %s: status %d", data, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("conjur variable %s not found", data))
+	case status >= 400:
+		return none, fmt.Errorf("unable to read conjur variable %s: status %d", data, status)
+	}
+
+	return redact.Sensitive(string(value)), nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	consulapi "github.com/hashicorp/consul/api"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the consul provider configuration.
+const (
+	consulKeyAddress    = "address"
+	consulKeyToken      = "token"
+	consulKeyDatacenter = "datacenter"
+)
+
+func init() {
+	Register(ConsulProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewConsulHandler(secret)
+	})
+}
+
+// ConsulHandler resolves placeholders of the form {{ consul:<secret>:<key> }}
+// against the Consul KV store. The key is the full KV path, e.g.
+// config/app/db_host.
+type ConsulHandler struct {
+	client     *consulapi.Client
+	datacenter string
+}
+
+// NewConsulHandler builds a ConsulHandler from a provider Secret.
+func NewConsulHandler(secret *corev1.Secret) (*ConsulHandler, error) {
+	address := string(secret.Data[consulKeyAddress])
+
+	if len(address) == 0 {
+		return nil, fmt.Errorf("consul provider secret %s is missing the address key", secret.Name)
+	}
+
+	config := consulapi.DefaultConfig()
+	config.Address = address
+	config.Token = string(secret.Data[consulKeyToken])
+
+	// The shared TLS keys build the client transport directly instead of
+	// going through the PEM fields of the Consul configuration, so spkiPin
+	// and the fips-mode restrictions apply like for every other provider.
+	tlsConfig, err := buildTLSConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		config.HttpClient = &nethttp.Client{
+			Transport: &nethttp.Transport{
+				TLSClientConfig: tlsConfig,
+				Proxy:           nethttp.ProxyFromEnvironment,
+			},
+		}
+	}
+
+	client, err := consulapi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create consul client: %w", err)
+	}
+
+	return &ConsulHandler{
+		client:     client,
+		datacenter: string(secret.Data[consulKeyDatacenter]),
+	}, nil
+}
+
+// Check verifies the Consul cluster is reachable and has a leader.
+func (h *ConsulHandler) Check(ctx context.Context) error {
+	leader, err := h.client.Status().LeaderWithQueryOptions(h.queryOptions(ctx))
+	if err != nil {
+		return fmt.Errorf("consul cluster unreachable: %w", err)
+	}
+
+	if len(leader) == 0 {
+		return fmt.Errorf("consul cluster has no leader")
+	}
+
+	return nil
+}
+
+// queryOptions builds the per-call options carrying the context and the
+// configured datacenter.
+func (h *ConsulHandler) queryOptions(ctx context.Context) *consulapi.QueryOptions {
+	options := &consulapi.QueryOptions{Datacenter: h.datacenter}
+
+	return options.WithContext(ctx)
+}
+
+// GetData reads the KV entry at the path data.
+func (h *ConsulHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	pair, _, err := h.client.KV().Get(data, h.queryOptions(ctx))
+	if err != nil {
+		if strings.Contains(err.Error(), "Permission denied") {
+			return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read consul key %s: %w", data, err))
+		}
+
+		return none, fmt.Errorf("unable to read consul key %s: %w", data, err)
+	}
+
+	if pair == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("consul key %s not found", data))
+	}
+
+	return redact.Sensitive(string(pair.Value)), nil
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the doppler provider configuration.
+const (
+	dopplerKeyToken   = "token"
+	dopplerKeyProject = "project"
+	dopplerKeyConfig  = "config"
+	dopplerKeyAPIURL  = "apiURL"
+)
+
+// dopplerDefaultAPIURL is the hosted Doppler API.
+const dopplerDefaultAPIURL = "https://api.doppler.com"
+
+func init() {
+	Register(DopplerProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewDopplerHandler(secret)
+	})
+}
+
+// DopplerHandler resolves placeholders of the form
+// {{ doppler:<secret>:<NAME> }} against a Doppler config using a service
+// token, so SaaS-managed secrets feed rendered manifests directly.
+type DopplerHandler struct {
+	client  *nethttp.Client
+	apiURL  string
+	token   string
+	project string
+	config  string
+}
+
+// NewDopplerHandler builds a DopplerHandler from a provider Secret. Service
+// tokens are scoped to one project and config, so those keys are optional
+// and only needed with personal or service-account tokens.
+func NewDopplerHandler(secret *corev1.Secret) (*DopplerHandler, error) {
+	token := string(secret.Data[dopplerKeyToken])
+
+	if len(token) == 0 {
+		return nil, fmt.Errorf("doppler provider secret %s is missing the token key", secret.Name)
+	}
+
+	apiURL := string(secret.Data[dopplerKeyAPIURL])
+
+	if len(apiURL) == 0 {
+		apiURL = dopplerDefaultAPIURL
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DopplerHandler{
+		client:  client,
+		apiURL:  apiURL,
+		token:   token,
+		project: string(secret.Data[dopplerKeyProject]),
+		config:  string(secret.Data[dopplerKeyConfig]),
+	}, nil
+}
+
+// secretURL builds the API URL reading one secret by name.
+func (h *DopplerHandler) secretURL(name string) string {
+	query := url.Values{"name": {name}}
+
+	if len(h.project) > 0 {
+		query.Set("project", h.project)
+	}
+
+	if len(h.config) > 0 {
+		query.Set("config", h.config)
+	}
+
+	return fmt.Sprintf("%s/v3/configs/config/secret?%s", h.apiURL, query.Encode())
+}
+
+// Check verifies the Doppler API accepts the token.
+func (h *DopplerHandler) Check(ctx context.Context) error {
+	headers := map[string]string{"Authorization": "Bearer " + h.token}
+
+	status, _, err := restRequest(ctx, h.client, nethttp.MethodGet, h.apiURL+"/v3/me", headers, nil)
+	if err != nil {
+		return err
+	}
+
+	if status >= 400 {
+		return fmt.Errorf("doppler API rejected the token: status %d", status)
+	}
+
+	return nil
+}
+
+// GetData reads the Doppler secret named data from the configured project
+// and config.
+func (h *DopplerHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	headers := map[string]string{"Authorization": "Bearer " + h.token}
+
+	status, body, err := restRequest(ctx, h.client, nethttp.MethodGet, h.secretURL(data), headers, nil)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read doppler secret %s: status %d", data, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("doppler secret %s not found", data))
+	case status >= 400:
+		return none, fmt.Errorf("unable to read doppler secret %s: status %d", data, status)
+	}
+
+	var response struct {
+		Value struct {
+			Raw *string `json:"raw"`
+		} `json:"value"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return none, fmt.Errorf("unable to parse doppler response for %s: %w", data, err)
+	}
+
+	if response.Value.Raw == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("doppler secret %s has no value", data))
+	}
+
+	return redact.Sensitive(*response.Value.Raw), nil
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the etcd provider configuration.
+const (
+	etcdKeyEndpoints = "endpoints"
+	etcdKeyUsername  = "username"
+	etcdKeyPassword  = "password"
+)
+
+func init() {
+	Register(EtcdProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewEtcdHandler(secret)
+	})
+}
+
+// EtcdHandler resolves placeholders of the form {{ etcd:<secret>:<key> }}
+// against an etcd cluster, for environments that keep configuration in a
+// dedicated etcd. The key is the full etcd key, e.g. /config/app/flag.
+type EtcdHandler struct {
+	client *clientv3.Client
+}
+
+// NewEtcdHandler builds an EtcdHandler from a provider Secret. Client
+// certificates for mTLS come through the shared TLS keys.
+func NewEtcdHandler(secret *corev1.Secret) (*EtcdHandler, error) {
+	endpoints := string(secret.Data[etcdKeyEndpoints])
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("etcd provider secret %s is missing the endpoints key", secret.Name)
+	}
+
+	tlsConfig, err := buildTLSConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	config := clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		Username:    string(secret.Data[etcdKeyUsername]),
+		Password:    string(secret.Data[etcdKeyPassword]),
+		DialTimeout: 10 * time.Second,
+		TLS:         tlsConfig,
+	}
+
+	client, err := clientv3.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create etcd client: %w", err)
+	}
+
+	return &EtcdHandler{client: client}, nil
+}
+
+// Check verifies the first endpoint of the etcd cluster responds.
+func (h *EtcdHandler) Check(ctx context.Context) error {
+	if _, err := h.client.Status(ctx, h.client.Endpoints()[0]); err != nil {
+		return fmt.Errorf("etcd cluster unreachable: %w", err)
+	}
+
+	return nil
+}
+
+// GetData reads the etcd key named data.
+func (h *EtcdHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	response, err := h.client.Get(ctx, data)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read etcd key %s: %w", data, err))
+		}
+
+		return none, fmt.Errorf("unable to read etcd key %s: %w", data, err)
+	}
+
+	if len(response.Kvs) == 0 {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("etcd key %s not found", data))
+	}
+
+	return redact.Sensitive(string(response.Kvs[0].Value)), nil
+}
+
+// Close releases the gRPC connection when the pool evicts this handler, so
+// rotated provider Secrets do not leak connections.
+func (h *EtcdHandler) Close() {
+	h.client.Close()
+}
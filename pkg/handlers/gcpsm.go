@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the gcpsm provider configuration.
+const (
+	gcpsmKeyCredentialsJSON = "credentialsJSON"
+)
+
+func init() {
+	Register(GCPSMProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewGCPSMHandler(secret)
+	})
+}
+
+// GCPSMHandler resolves placeholders of the form
+// {{ gcpsm:<secret>:projects/<p>/secrets/<name>@<version> }} against Google
+// Secret Manager. The version suffix is optional and defaults to "latest".
+type GCPSMHandler struct {
+	client *secretmanager.Client
+}
+
+// NewGCPSMHandler builds a GCPSMHandler from a provider Secret. When the
+// Secret carries no service-account JSON key, the SDK default chain is used,
+// so GKE Workload Identity works without keys in the Secret.
+func NewGCPSMHandler(secret *corev1.Secret) (*GCPSMHandler, error) {
+	// The Secret Manager client speaks gRPC, whose cipher configuration the
+	// fips restrictions cannot reach; fail closed rather than claim a
+	// coverage the transport does not provide.
+	if fipsMode {
+		return nil, fmt.Errorf("gcpsm provider secret %s: the gcpsm provider cannot enforce FIPS-restricted TLS and is disabled under --fips-mode", secret.Name)
+	}
+
+	var opts []option.ClientOption
+
+	if credentialsJSON := secret.Data[gcpsmKeyCredentialsJSON]; len(credentialsJSON) > 0 {
+		opts = append(opts, option.WithCredentialsJSON(credentialsJSON))
+	}
+
+	client, err := secretmanager.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create secret manager client: %w", err)
+	}
+
+	return &GCPSMHandler{client: client}, nil
+}
+
+// GetData reads the secret version named by data, of the form
+// projects/<p>/secrets/<name> with an optional @<version> suffix.
+func (h *GCPSMHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	name := data
+	version := "latest"
+
+	if at := strings.LastIndex(data, "@"); at >= 0 {
+		name = data[:at]
+		version = data[at+1:]
+	}
+
+	request := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("%s/versions/%s", name, version),
+	}
+
+	response, err := h.client.AccessSecretVersion(ctx, request)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("secret manager version %s not found", request.Name))
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read secret manager version %s: %w", request.Name, err))
+		}
+
+		return none, fmt.Errorf("unable to read secret manager version %s: %w", request.Name, err)
+	}
+
+	if response.Payload == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("secret manager version %s has no payload", request.Name))
+	}
+
+	return redact.Sensitive(string(response.Payload.Data)), nil
+}
+
+// Close releases the gRPC connection when the pool evicts this handler, so
+// rotated provider Secrets do not leak connections.
+func (h *GCPSMHandler) Close() {
+	h.client.Close()
+}
@@ -0,0 +1,1633 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the git provider configuration.
+const (
+	gitKeyURL        = "url"
+	gitKeyBranch     = "branch"
+	gitKeyTag        = "tag"
+	gitKeyCommit     = "commit"
+	gitKeyFile       = "file"
+	gitKeyFileType   = "fileType"
+	gitKeyRefresh    = "refreshInterval"
+	gitKeyUsername   = "username"
+	gitKeyPassword   = "password"
+	gitKeySOPSAgeKey = "sopsAgeKey"
+	gitKeyLFS        = "lfs"
+
+	// gitKeyRecurseSubmodules clones submodules as well, for repositories
+	// whose configuration lives in one.
+	gitKeyRecurseSubmodules = "recurseSubmodules"
+
+	// SSH authentication keys. knownHosts is required with sshPrivateKey
+	// unless host key verification is explicitly skipped.
+	gitKeySSHPrivateKey       = "sshPrivateKey"
+	gitKeySSHPassphrase       = "sshPassphrase"
+	gitKeyKnownHosts          = "knownHosts"
+	gitKeyInsecureSkipHostKey = "insecureSkipHostKey"
+
+	// GitHub App authentication keys; installation tokens are minted on
+	// demand instead of storing a long-lived PAT.
+	gitKeyGitHubAppID          = "githubAppID"
+	gitKeyGitHubInstallationID = "githubAppInstallationID"
+	gitKeyGitHubPrivateKey     = "githubAppPrivateKey"
+
+	// Corporate proxy the git host is reached through.
+	gitKeyProxyURL      = "proxyURL"
+	gitKeyProxyUsername = "proxyUsername"
+	gitKeyProxyPassword = "proxyPassword"
+)
+
+// gitRefreshTimeout bounds one background refresh of a cached clone.
+const gitRefreshTimeout = 2 * time.Minute
+
+// gitHubAPIURL is the GitHub API endpoint installation tokens are minted
+// against.
+const gitHubAPIURL = "https://api.github.com"
+
+// gitHubTokenLeeway is how long before an installation token expires a
+// fresh one is minted.
+const gitHubTokenLeeway = 5 * time.Minute
+
+func init() {
+	Register(GitProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewGitHandler(secret)
+	})
+}
+
+// GitHandler resolves placeholders of the form {{ git:<secret>:<key> }}
+// against a configuration file stored in a git repository. The provider
+// Secret supplies the connection data under the keys "url", "branch" and
+// "file", plus optional "username" and "password" credentials. The format of
+// the file is selected by "fileType": yaml (the default), json, toml, ini,
+// env or properties.
+type GitHandler struct {
+	url      string
+	branch   string
+	tag      string
+	commit   string
+	file     string
+	fileType string
+	username string
+	password string
+
+	// sopsAgeKey, when set, is the age identity used to decrypt
+	// SOPS-encrypted configuration files before key lookup, so encrypted
+	// config can live in git and only be decrypted at render time.
+	sopsAgeKey string
+
+	// auth carries the transport credentials: SSH public key auth when the
+	// Secret holds sshPrivateKey, HTTP basic auth when it holds username,
+	// and a minted installation token for GitHub App authentication.
+	auth transport.AuthMethod
+
+	// GitHub App credentials; the app signs a short-lived JWT with its
+	// private key and exchanges it for an installation token, refreshed
+	// shortly before expiry.
+	ghAppID          string
+	ghInstallationID string
+	ghPrivateKey     *rsa.PrivateKey
+	ghMu             sync.Mutex
+	ghTokenExpiry    time.Time
+
+	// knownHostsPath is the temporary file the knownHosts content is staged
+	// in for the ssh host key callback; removed on Close.
+	knownHostsPath string
+
+	// caBundle holds an additional PEM CA bundle trusted for this
+	// repository, so self-hosted git servers with private CAs verify
+	// without loosening TLS.
+	caBundle []byte
+
+	// proxy routes clones and fetches through a corporate proxy when its
+	// URL is set.
+	proxy transport.ProxyOptions
+
+	// insecureSkipTLS disables TLS certificate verification for this
+	// repository. It must be requested explicitly through the provider
+	// Secret and is rejected entirely when the operator runs with
+	// --forbid-insecure-tls.
+	insecureSkipTLS bool
+
+	// refreshInterval, when set, keeps the cached clone warm: a background
+	// refresher fetches on this interval and reads inside it are served from
+	// the cached checkout without touching the remote. lastSync is guarded
+	// by the repository lock; stopRefresh ends the refresher on Close.
+	refreshInterval time.Duration
+	lastSync        time.Time
+	stopRefresh     chan struct{}
+
+	// recurseSubmodules initializes and updates submodules on clone and
+	// fetch, so files inside them are resolvable like any other path.
+	recurseSubmodules bool
+
+	// lfs enables Git LFS resolution: files that read as pointer blobs are
+	// replaced by the real object fetched through the LFS batch API with
+	// lfsClient, which carries the repository TLS settings.
+	lfs       bool
+	lfsClient *nethttp.Client
+
+	// flight coalesces concurrent reads of the same reference into a single
+	// clone, so a mass resync doesn't multiply network traffic.
+	flight singleflight.Group
+}
+
+// repoLocks serializes access to the cached clone of one (repository,
+// reference) pair across handler instances, so provider Secrets pointing at
+// different branches of the same repository get their own directory (see
+// cacheDir) and concurrent reconciles never race inside one checkout.
+var repoLocks sync.Map
+
+// noPlaintextOnDisk forces git content to stay in memory. Set from the
+// --no-plaintext-on-disk flag; see SetNoPlaintextOnDisk.
+var noPlaintextOnDisk bool
+
+// SetNoPlaintextOnDisk configures whether fetched git content may be cached
+// on disk. When disabled, every read clones into an in-memory filesystem, so
+// node ephemeral storage never contains repository content. Reads lose the
+// on-disk clone cache and pay a full shallow clone, coalesced per reference
+// by singleflight.
+func SetNoPlaintextOnDisk(enabled bool) {
+	noPlaintextOnDisk = enabled
+}
+
+// NewGitHandler builds a GitHandler from a provider Secret.
+func NewGitHandler(secret *corev1.Secret) (*GitHandler, error) {
+	h := &GitHandler{
+		url:             string(secret.Data[gitKeyURL]),
+		branch:          string(secret.Data[gitKeyBranch]),
+		tag:             string(secret.Data[gitKeyTag]),
+		commit:          string(secret.Data[gitKeyCommit]),
+		file:            string(secret.Data[gitKeyFile]),
+		username:        string(secret.Data[gitKeyUsername]),
+		password:        string(secret.Data[gitKeyPassword]),
+		caBundle:        secret.Data[tlsKeyCACert],
+		insecureSkipTLS: string(secret.Data[tlsKeyInsecureSkipTLS]) == "true",
+		proxy: transport.ProxyOptions{
+			URL:      string(secret.Data[gitKeyProxyURL]),
+			Username: string(secret.Data[gitKeyProxyUsername]),
+			Password: string(secret.Data[gitKeyProxyPassword]),
+		},
+		sopsAgeKey:        string(secret.Data[gitKeySOPSAgeKey]),
+		recurseSubmodules: string(secret.Data[gitKeyRecurseSubmodules]) == "true",
+	}
+
+	if h.insecureSkipTLS {
+		if err := checkInsecureTLSAllowed(secret.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(h.url) == 0 {
+		return nil, fmt.Errorf("git provider secret %s is missing the url key", secret.Name)
+	}
+
+	// file may be omitted when every placeholder names its own file with
+	// the <file>#<key> form.
+	h.fileType = string(secret.Data[gitKeyFileType])
+
+	switch h.fileType {
+	case "":
+		h.fileType = "yaml"
+	case "yaml", "json", "toml", "ini", "env", "properties":
+	default:
+		return nil, fmt.Errorf("git provider secret %s has unsupported fileType %q", secret.Name, h.fileType)
+	}
+
+	// Branch, tag and commit are mutually exclusive; tags and commits pin
+	// the render to an immutable revision.
+	references := 0
+
+	for _, reference := range []string{h.branch, h.tag, h.commit} {
+		if len(reference) > 0 {
+			references++
+		}
+	}
+
+	if references > 1 {
+		return nil, fmt.Errorf("git provider secret %s must set at most one of branch, tag or commit", secret.Name)
+	}
+
+	if references == 0 {
+		h.branch = "main"
+	}
+
+	if err := h.buildAuth(secret); err != nil {
+		return nil, err
+	}
+
+	if string(secret.Data[gitKeyLFS]) == "true" {
+		client, err := newRESTClient(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		client.Timeout = lfsTimeout
+		h.lfs = true
+		h.lfsClient = client
+	}
+
+	if refresh := string(secret.Data[gitKeyRefresh]); len(refresh) > 0 {
+		interval, err := time.ParseDuration(refresh)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("git provider secret %s has invalid refreshInterval %q", secret.Name, refresh)
+		}
+
+		h.refreshInterval = interval
+	}
+
+	// The background refresher needs an on-disk checkout to keep warm; in
+	// memory mode every read clones anyway.
+	if h.refreshInterval > 0 && !noPlaintextOnDisk {
+		h.stopRefresh = make(chan struct{})
+
+		go h.refreshLoop()
+	}
+
+	return h, nil
+}
+
+// buildAuth prepares the transport credentials of this handler. An SSH
+// private key enables public key auth and requires host key verification
+// material: the knownHosts entries of the server, or the explicit
+// insecureSkipHostKey opt-out, which falls under the same policy as
+// insecureSkipTLS.
+func (h *GitHandler) buildAuth(secret *corev1.Secret) error {
+	if appID := string(secret.Data[gitKeyGitHubAppID]); len(appID) > 0 {
+		return h.buildGitHubAppAuth(secret, appID)
+	}
+
+	privateKey := secret.Data[gitKeySSHPrivateKey]
+
+	if len(privateKey) == 0 {
+		if len(h.username) > 0 {
+			h.auth = &http.BasicAuth{Username: h.username, Password: h.password}
+		}
+
+		return nil
+	}
+
+	auth, err := gitssh.NewPublicKeys("git", privateKey, string(secret.Data[gitKeySSHPassphrase]))
+	if err != nil {
+		return fmt.Errorf("git provider secret %s has an unusable sshPrivateKey: %w", secret.Name, err)
+	}
+
+	knownHosts := secret.Data[gitKeyKnownHosts]
+
+	switch {
+	case string(secret.Data[gitKeyInsecureSkipHostKey]) == "true":
+		if err := checkInsecureTLSAllowed(secret.Name); err != nil {
+			return err
+		}
+
+		auth.HostKeyCallback = cryptossh.InsecureIgnoreHostKey()
+	case len(knownHosts) > 0:
+		// Staging the entries in a temporary file would defeat the plaintext
+		// policy; match them in memory instead.
+		if noPlaintextOnDisk {
+			callback, err := inMemoryHostKeyCallback(knownHosts)
+			if err != nil {
+				return fmt.Errorf("git provider secret %s has invalid knownHosts: %w", secret.Name, err)
+			}
+
+			auth.HostKeyCallback = callback
+
+			break
+		}
+
+		// The knownhosts callback only reads files; stage the Secret
+		// content in one.
+		file, err := os.CreateTemp("", "dri-known-hosts-*")
+		if err != nil {
+			return fmt.Errorf("unable to stage knownHosts of git provider secret %s: %w", secret.Name, err)
+		}
+
+		if _, err := file.Write(knownHosts); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+
+			return fmt.Errorf("unable to stage knownHosts of git provider secret %s: %w", secret.Name, err)
+		}
+
+		file.Close()
+		h.knownHostsPath = file.Name()
+
+		callback, err := gitssh.NewKnownHostsCallback(h.knownHostsPath)
+		if err != nil {
+			return fmt.Errorf("git provider secret %s has invalid knownHosts: %w", secret.Name, err)
+		}
+
+		auth.HostKeyCallback = callback
+	default:
+		return fmt.Errorf("git provider secret %s uses sshPrivateKey but has no knownHosts; add the host entries or set insecureSkipHostKey", secret.Name)
+	}
+
+	h.auth = auth
+
+	return nil
+}
+
+// inMemoryHostKeyCallback builds a host key callback directly from the
+// knownHosts bytes of the provider Secret, used under --no-plaintext-on-disk
+// where the entries must not be staged in a temporary file. Plain and hashed
+// host patterns are supported; @cert-authority entries are not.
+func inMemoryHostKeyCallback(data []byte) (cryptossh.HostKeyCallback, error) {
+	type knownHostsEntry struct {
+		revoked bool
+		hosts   []string
+		key     cryptossh.PublicKey
+	}
+
+	var entries []knownHostsEntry
+
+	for rest := data; len(rest) > 0; {
+		marker, hosts, key, _, remainder, err := cryptossh.ParseKnownHosts(rest)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid knownHosts entry: %w", err)
+		}
+
+		rest = remainder
+
+		if marker == "cert-authority" {
+			continue
+		}
+
+		entries = append(entries, knownHostsEntry{revoked: marker == "revoked", hosts: hosts, key: key})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("knownHosts holds no usable entries")
+	}
+
+	return func(hostname string, remote net.Addr, key cryptossh.PublicKey) error {
+		candidates := []string{knownhosts.Normalize(hostname)}
+
+		if remote != nil {
+			candidates = append(candidates, knownhosts.Normalize(remote.String()))
+		}
+
+		mismatch := false
+
+		for _, entry := range entries {
+			if !knownHostMatches(entry.hosts, candidates) {
+				continue
+			}
+
+			if entry.key.Type() == key.Type() && bytes.Equal(entry.key.Marshal(), key.Marshal()) {
+				if entry.revoked {
+					return fmt.Errorf("host key for %s is revoked in knownHosts", hostname)
+				}
+
+				return nil
+			}
+
+			mismatch = true
+		}
+
+		if mismatch {
+			return fmt.Errorf("host key mismatch for %s", hostname)
+		}
+
+		return fmt.Errorf("host %s is not in knownHosts", hostname)
+	}, nil
+}
+
+// knownHostMatches reports whether any host pattern of one knownHosts entry
+// matches any candidate address. Hashed patterns (|1|salt|hash|) compare the
+// HMAC-SHA1 of the candidate under the entry salt, as OpenSSH does.
+func knownHostMatches(patterns, candidates []string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "|1|") {
+			parts := strings.Split(pattern, "|")
+			if len(parts) != 4 {
+				continue
+			}
+
+			salt, err := base64.StdEncoding.DecodeString(parts[2])
+			if err != nil {
+				continue
+			}
+
+			want, err := base64.StdEncoding.DecodeString(parts[3])
+			if err != nil {
+				continue
+			}
+
+			for _, candidate := range candidates {
+				mac := hmac.New(sha1.New, salt)
+				mac.Write([]byte(candidate))
+
+				if hmac.Equal(mac.Sum(nil), want) {
+					return true
+				}
+			}
+
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if pattern == candidate {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildGitHubAppAuth parses the GitHub App credentials. The installation
+// token itself is minted lazily by ensureAuth, since it only lives an hour.
+func (h *GitHandler) buildGitHubAppAuth(secret *corev1.Secret, appID string) error {
+	h.ghAppID = appID
+	h.ghInstallationID = string(secret.Data[gitKeyGitHubInstallationID])
+
+	if len(h.ghInstallationID) == 0 {
+		return fmt.Errorf("git provider secret %s is missing the githubAppInstallationID key required by githubAppID", secret.Name)
+	}
+
+	block, _ := pem.Decode(secret.Data[gitKeyGitHubPrivateKey])
+	if block == nil {
+		return fmt.Errorf("git provider secret %s is missing a PEM githubAppPrivateKey", secret.Name)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("git provider secret %s has an unusable githubAppPrivateKey: %w", secret.Name, err)
+		}
+
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("git provider secret %s has a non-RSA githubAppPrivateKey; GitHub Apps sign with RSA", secret.Name)
+		}
+
+		key = rsaKey
+	}
+
+	h.ghPrivateKey = key
+
+	return nil
+}
+
+// githubAppJWT signs the short-lived RS256 JWT a GitHub App presents when
+// minting installation tokens.
+func (h *GitHandler) githubAppJWT() (string, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+	now := time.Now()
+
+	header := encode([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims := encode([]byte(fmt.Sprintf(`{"iat":%d,"exp":%d,"iss":%q}`,
+		now.Add(-time.Minute).Unix(), now.Add(9*time.Minute).Unix(), h.ghAppID)))
+
+	signing := header + "." + claims
+	digest := sha256.Sum256([]byte(signing))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, h.ghPrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("unable to sign GitHub App JWT: %w", err)
+	}
+
+	return signing + "." + encode(signature), nil
+}
+
+// ensureAuth mints a fresh GitHub App installation token when the current
+// one is about to expire. It is a no-op for the other auth methods, whose
+// credentials are static.
+func (h *GitHandler) ensureAuth(ctx context.Context) error {
+	if h.ghPrivateKey == nil {
+		return nil
+	}
+
+	h.ghMu.Lock()
+	defer h.ghMu.Unlock()
+
+	if time.Until(h.ghTokenExpiry) > gitHubTokenLeeway {
+		return nil
+	}
+
+	jwt, err := h.githubAppJWT()
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{
+		"Authorization": "Bearer " + jwt,
+		"Accept":        "application/vnd.github+json",
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", gitHubAPIURL, h.ghInstallationID)
+	client := &nethttp.Client{Timeout: 30 * time.Second}
+
+	status, body, err := restRequest(ctx, client, nethttp.MethodPost, url, headers, nil)
+	if err != nil {
+		return err
+	}
+
+	if status >= 400 {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to mint GitHub App installation token: status %d", status))
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("unable to parse GitHub App token response: %w", err)
+	}
+
+	if len(response.Token) == 0 {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("GitHub App token response carries no token"))
+	}
+
+	// Installation tokens authenticate like a password over HTTPS.
+	h.auth = &http.BasicAuth{Username: "x-access-token", Password: response.Token}
+	h.ghTokenExpiry = response.ExpiresAt
+
+	if h.ghTokenExpiry.IsZero() {
+		h.ghTokenExpiry = time.Now().Add(55 * time.Minute)
+	}
+
+	return nil
+}
+
+// Close stops the background refresher and removes the staged knownHosts
+// file. Called when the handler is evicted from the pool because its
+// provider Secret changed.
+func (h *GitHandler) Close() {
+	if h.stopRefresh != nil {
+		close(h.stopRefresh)
+		h.stopRefresh = nil
+	}
+
+	if len(h.knownHostsPath) > 0 {
+		os.Remove(h.knownHostsPath)
+		h.knownHostsPath = ""
+	}
+}
+
+// refreshLoop fetches the cached clone on the configured interval, so reads
+// between refreshes are served from the warm checkout without a remote
+// round-trip.
+func (h *GitHandler) refreshLoop() {
+	ticker := time.NewTicker(h.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stopRefresh:
+			return
+		case <-ticker.C:
+			h.refresh()
+		}
+	}
+}
+
+// refresh performs one background synchronization of the cached clone.
+// Failures are logged and retried on the next tick; reads keep being served
+// from the last good checkout in the meantime.
+func (h *GitHandler) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), gitRefreshTimeout)
+	defer cancel()
+
+	lock, _ := repoLocks.LoadOrStore(h.url+"|"+h.reference(), &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	dir := h.cacheDir()
+
+	if err := h.ensureAuth(ctx); err != nil {
+		ctrl.Log.WithName("git-refresh").Error(err, "unable to refresh cached clone", "repository", redact.URL(h.url))
+
+		return
+	}
+
+	if err := h.syncRepo(ctx, dir); err != nil {
+		ctrl.Log.WithName("git-refresh").Error(err, "unable to refresh cached clone", "repository", redact.URL(h.url))
+
+		return
+	}
+
+	h.lastSync = time.Now()
+	cloneCache.touch(dir, h.url+"|"+h.reference())
+}
+
+// reference identifies the revision this handler renders from, used in
+// cache and lock keys so different revisions of one repository never share a
+// checkout.
+func (h *GitHandler) reference() string {
+	switch {
+	case len(h.commit) > 0:
+		return "commit:" + h.commit
+	case len(h.tag) > 0:
+		return "tag:" + h.tag
+	default:
+		return "branch:" + h.branch
+	}
+}
+
+// Check verifies the git remote is reachable by listing its references.
+func (h *GitHandler) Check(ctx context.Context) error {
+	if err := h.ensureAuth(ctx); err != nil {
+		return err
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{h.url},
+	})
+
+	options := &git.ListOptions{InsecureSkipTLS: h.insecureSkipTLS, CABundle: h.caBundle}
+	options.Auth = h.auth
+	options.ProxyOptions = h.proxy
+
+	if _, err := remote.ListContext(ctx, options); err != nil {
+		return fmt.Errorf("git remote %s unreachable: %w", redact.URL(h.url), err)
+	}
+
+	return nil
+}
+
+// GetData clones the repository, parses the configuration file and returns
+// the value for data, where data is a dot separated path of keys. A
+// <file>#<key> form selects another file of the repository instead of the
+// configured one, so a single provider Secret can serve many files.
+// Concurrent calls for the same reference share one clone.
+func (h *GitHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	// A file(<path>) directive returns the whole file instead of one key.
+	if strings.HasPrefix(data, "file(") {
+		return h.getFileContent(ctx, data)
+	}
+
+	file := h.file
+	key := data
+
+	if before, after, found := strings.Cut(data, "#"); found {
+		file = before
+		key = after
+	}
+
+	if len(file) == 0 {
+		return redact.SensitiveString{}, fmt.Errorf("git placeholder %q names no file and the provider secret has no file key", data)
+	}
+
+	// A glob in the key returns every matching entry instead of one value.
+	if strings.Contains(key, "*") {
+		return h.getMatchingData(ctx, file, key)
+	}
+
+	flightKey := strings.Join([]string{h.url, h.reference(), file, key}, "|")
+
+	value, err, _ := h.flight.Do(flightKey, func() (interface{}, error) {
+		return h.getData(ctx, file, key)
+	})
+	if err != nil {
+		return redact.SensitiveString{}, err
+	}
+
+	return redact.Sensitive(value.(string)), nil
+}
+
+// getFileContent serves a file(<path>) placeholder: the whole file content,
+// transformed by the optional |base64 and |indent<n> modifiers, so
+// certificates or config blobs stored in git embed into rendered manifests.
+func (h *GitHandler) getFileContent(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	closing := strings.Index(data, ")")
+	if closing < 0 {
+		return none, fmt.Errorf("git placeholder %q is missing the closing parenthesis of file()", data)
+	}
+
+	file := data[len("file("):closing]
+
+	if len(file) == 0 {
+		return none, fmt.Errorf("git placeholder %q names no file", data)
+	}
+
+	var modifiers []string
+
+	if rest := data[closing+1:]; len(rest) > 0 {
+		if !strings.HasPrefix(rest, "|") {
+			return none, fmt.Errorf("git placeholder %q has trailing characters after file(); modifiers start with |", data)
+		}
+
+		modifiers = strings.Split(rest[1:], "|")
+	}
+
+	flightKey := strings.Join([]string{h.url, h.reference(), "file", data}, "|")
+
+	value, err, _ := h.flight.Do(flightKey, func() (interface{}, error) {
+		content, err := h.readFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+
+		return applyModifiers(string(content), modifiers)
+	})
+	if err != nil {
+		return none, err
+	}
+
+	return redact.Sensitive(value.(string)), nil
+}
+
+// applyModifiers transforms a resolved value left to right: base64 encodes
+// it, and indent<n> prefixes every line but the first with n spaces so a
+// multi-line value can sit at the insertion column of a YAML block.
+func applyModifiers(value string, modifiers []string) (string, error) {
+	for _, modifier := range modifiers {
+		switch {
+		case modifier == "base64":
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		case strings.HasPrefix(modifier, "indent"):
+			width, err := strconv.Atoi(strings.TrimPrefix(modifier, "indent"))
+			if err != nil || width < 0 {
+				return "", fmt.Errorf("invalid indent modifier %q", modifier)
+			}
+
+			value = strings.ReplaceAll(value, "\n", "\n"+strings.Repeat(" ", width))
+		default:
+			return "", fmt.Errorf("unknown modifier %q", modifier)
+		}
+	}
+
+	return value, nil
+}
+
+// getMatchingData serves a glob placeholder such as FEATURE_*: every key of
+// the file matching the pattern is rendered as a "key: value" line, sorted
+// by key, so a group of related settings injects without listing each one.
+// Modifiers after a | transform the rendered block, e.g. indent4 to place
+// the lines at the insertion column of a YAML mapping.
+func (h *GitHandler) getMatchingData(ctx context.Context, file, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	pattern := data
+
+	var modifiers []string
+
+	if before, after, found := strings.Cut(data, "|"); found {
+		pattern = before
+		modifiers = strings.Split(after, "|")
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return none, fmt.Errorf("git placeholder %q has an invalid glob: %w", data, err)
+	}
+
+	flightKey := strings.Join([]string{h.url, h.reference(), file, data}, "|")
+
+	value, err, _ := h.flight.Do(flightKey, func() (interface{}, error) {
+		content, err := h.readFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+
+		matches, err := h.extractMatching(content, file, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("no key matches %s in file %s from repository %s", pattern, file, redact.URL(h.url)))
+		}
+
+		keys := make([]string, 0, len(matches))
+
+		for key := range matches {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		lines := make([]string, 0, len(keys))
+
+		for _, key := range keys {
+			lines = append(lines, key+": "+matches[key])
+		}
+
+		return applyModifiers(strings.Join(lines, "\n"), modifiers)
+	})
+	if err != nil {
+		return none, err
+	}
+
+	return redact.Sensitive(value.(string)), nil
+}
+
+// extractMatching collects the scalar entries of the file whose key matches
+// the glob pattern. For nested formats only the last path segment may carry
+// the glob; earlier segments are followed literally, so app.FEATURE_* reads
+// inside the app mapping.
+func (h *GitHandler) extractMatching(content []byte, file, pattern string) (map[string]string, error) {
+	fileType := h.fileTypeFor(file)
+
+	switch fileType {
+	case "ini":
+		section := ""
+		keyPattern := pattern
+
+		if dot := strings.Index(pattern, "."); dot >= 0 {
+			section = pattern[:dot]
+			keyPattern = pattern[dot+1:]
+		}
+
+		return matchEntries(collectINIEntries(content, section), keyPattern), nil
+	case "env":
+		return matchEntries(collectDotenvEntries(content), pattern), nil
+	case "properties":
+		return matchEntries(collectPropertiesEntries(content), pattern), nil
+	}
+
+	values := map[string]interface{}{}
+
+	var err error
+
+	switch fileType {
+	case "json":
+		err = json.Unmarshal(content, &values)
+	case "toml":
+		values, err = parseTOML(content)
+	default:
+		err = yaml.Unmarshal(content, &values)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+
+	if isSOPSEncrypted(values) {
+		if len(h.sopsAgeKey) == 0 {
+			return nil, fmt.Errorf("file %s from repository %s is SOPS-encrypted but the provider secret has no sopsAgeKey", file, redact.URL(h.url))
+		}
+
+		if err := decryptSOPS(values, h.sopsAgeKey); err != nil {
+			return nil, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err)
+		}
+	}
+
+	parts := strings.Split(pattern, ".")
+
+	for _, part := range parts[:len(parts)-1] {
+		if strings.Contains(part, "*") {
+			return nil, fmt.Errorf("git placeholder glob %s: only the last path segment may contain *", pattern)
+		}
+
+		child, ok := toStringMap(values[part])
+		if !ok {
+			return map[string]string{}, nil
+		}
+
+		values = child
+	}
+
+	matches := map[string]string{}
+
+	for key, value := range values {
+		if matched, _ := path.Match(parts[len(parts)-1], key); !matched {
+			continue
+		}
+
+		// Only scalar entries render as key: value lines; nested mappings
+		// and lists are skipped.
+		switch value.(type) {
+		case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+			continue
+		}
+
+		matches[key] = fmt.Sprintf("%v", value)
+	}
+
+	return matches, nil
+}
+
+// matchEntries filters flat entries down to the keys matching the glob
+// pattern.
+func matchEntries(entries map[string]string, pattern string) map[string]string {
+	matches := map[string]string{}
+
+	for key, value := range entries {
+		if matched, _ := path.Match(pattern, key); matched {
+			matches[key] = value
+		}
+	}
+
+	return matches
+}
+
+func (h *GitHandler) getData(ctx context.Context, file, key string) (string, error) {
+	content, err := h.readFile(ctx, file)
+	if err != nil {
+		return "", err
+	}
+
+	return h.extractValue(content, file, key)
+}
+
+// readFile returns the content of one file at the configured reference,
+// from the cached checkout or an in-memory clone depending on the plaintext
+// policy.
+func (h *GitHandler) readFile(ctx context.Context, file string) ([]byte, error) {
+	if err := h.ensureAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	if noPlaintextOnDisk {
+		content, err := h.readFileInMemory(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.resolveLFS(ctx, content, file)
+	}
+
+	lock, _ := repoLocks.LoadOrStore(h.url+"|"+h.reference(), &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	dir := h.cacheDir()
+
+	// With a refresh interval the checkout is kept warm in the background;
+	// reads inside the interval skip the remote round-trip entirely.
+	if h.refreshInterval == 0 || time.Since(h.lastSync) >= h.refreshInterval {
+		if err := h.syncRepo(ctx, dir); err != nil {
+			return nil, err
+		}
+
+		h.lastSync = time.Now()
+	}
+
+	cloneCache.touch(dir, h.url+"|"+h.reference())
+
+	path, err := h.resolveFilePath(dir, file)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s not found in repository %s", file, redact.URL(h.url)))
+		}
+
+		return nil, fmt.Errorf("unable to read file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+
+	return h.resolveLFS(ctx, content, file)
+}
+
+// readFileInMemory clones the repository into an in-memory filesystem and
+// reads one file from there, so no repository content touches the node
+// filesystem.
+func (h *GitHandler) readFileInMemory(ctx context.Context, file string) ([]byte, error) {
+	clean := filepath.ToSlash(filepath.Clean(file))
+
+	if filepath.IsAbs(file) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return nil, fmt.Errorf("file %s escapes the repository root", file)
+	}
+
+	worktree := memfs.New()
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), worktree, h.cloneOptions())
+	if err != nil {
+		if errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed) {
+			return nil, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to clone repository %s: %w", redact.URL(h.url), err))
+		}
+
+		return nil, fmt.Errorf("unable to clone repository %s: %w", redact.URL(h.url), err)
+	}
+
+	if err := h.checkoutCommit(ctx, repo); err != nil {
+		return nil, err
+	}
+
+	handle, err := worktree.Open(clean)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s not found in repository %s", file, redact.URL(h.url)))
+		}
+
+		return nil, fmt.Errorf("unable to read file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+	defer handle.Close()
+
+	content, err := io.ReadAll(handle)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+
+	return content, nil
+}
+
+// fileTypeFor returns the format of one file: a recognized extension wins,
+// so a Secret serving several files can mix formats, and the configured
+// fileType remains the fallback.
+func (h *GitHandler) fileTypeFor(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	case ".ini":
+		return "ini"
+	case ".env":
+		return "env"
+	case ".properties":
+		return "properties"
+	}
+
+	return h.fileType
+}
+
+// extractValue parses the fetched configuration file according to its
+// format and returns the value at the dot separated key path.
+func (h *GitHandler) extractValue(content []byte, file, data string) (string, error) {
+	values := map[string]interface{}{}
+
+	var err error
+
+	switch h.fileTypeFor(file) {
+	case "json":
+		err = json.Unmarshal(content, &values)
+	case "toml":
+		values, err = parseTOML(content)
+	case "ini":
+		value, err := lookupINIKey(content, data)
+		if err != nil {
+			return "", errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err))
+		}
+
+		return value, nil
+	case "env":
+		value, err := lookupDotenvKey(content, data)
+		if err != nil {
+			return "", errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err))
+		}
+
+		return value, nil
+	case "properties":
+		value, err := lookupPropertiesKey(content, data)
+		if err != nil {
+			return "", errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err))
+		}
+
+		return value, nil
+	default:
+		err = yaml.Unmarshal(content, &values)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to parse file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+
+	if isSOPSEncrypted(values) {
+		if len(h.sopsAgeKey) == 0 {
+			return "", fmt.Errorf("file %s from repository %s is SOPS-encrypted but the provider secret has no sopsAgeKey", file, redact.URL(h.url))
+		}
+
+		if err := decryptSOPS(values, h.sopsAgeKey); err != nil {
+			return "", fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err)
+		}
+	}
+
+	value, err := lookupKey(values, data)
+	if err != nil {
+		return "", errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err))
+	}
+
+	return value, nil
+}
+
+// resolveFilePath canonicalizes a file inside the clone at dir and rejects
+// any path that escapes the repository root, whether through an absolute
+// path, ".." segments or a symlink committed to the repository.
+func (h *GitHandler) resolveFilePath(dir, file string) (string, error) {
+	if filepath.IsAbs(file) {
+		return "", fmt.Errorf("file %s must be a path relative to the repository root", file)
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve repository root: %w", err)
+	}
+
+	path := filepath.Join(root, file)
+
+	// Join cleans the path, so ".." segments that climb out of the clone
+	// show up as a result outside the root.
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s escapes the repository root", file)
+	}
+
+	// A symlink committed to the repository can still point outside; resolve
+	// it and check again. A missing file is reported by the caller.
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return path, nil
+		}
+
+		return "", fmt.Errorf("unable to resolve file %s: %w", file, err)
+	}
+
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("file %s resolves outside the repository root", file)
+	}
+
+	return resolved, nil
+}
+
+// cacheDir is the stable location of the cached clone for this repository
+// and branch.
+func (h *GitHandler) cacheDir() string {
+	sum := sha256.Sum256([]byte(h.url + "|" + h.reference()))
+
+	return filepath.Join(os.TempDir(), "operator-data-replace-inline", hex.EncodeToString(sum[:])[:16])
+}
+
+// syncRepo brings the cached clone at dir up to date with the remote branch,
+// cloning it first when missing. Existing clones are updated with an
+// incremental fetch and a hard reset to the remote head, which is cheaper
+// than a pull and survives force-pushed or rebased branches. An unusable
+// cached clone is discarded and cloned from scratch.
+func (h *GitHandler) syncRepo(ctx context.Context, dir string) error {
+	if repo, err := git.PlainOpen(dir); err == nil {
+		err := h.fetchAndReset(ctx, repo)
+		if err == nil {
+			return nil
+		}
+
+		// Rejected credentials are a problem of this provider Secret, not of
+		// the cached clone: keep the clone, which other Secrets pointing at
+		// the same repository may still use with working credentials.
+		if errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed) {
+			return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to fetch repository %s: %w", redact.URL(h.url), err))
+		}
+
+		os.RemoveAll(dir)
+	}
+
+	repo, err := git.PlainCloneContext(ctx, dir, false, h.cloneOptions())
+	if err != nil {
+		if errors.Is(err, transport.ErrAuthenticationRequired) || errors.Is(err, transport.ErrAuthorizationFailed) {
+			return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to clone repository %s: %w", redact.URL(h.url), err))
+		}
+
+		return fmt.Errorf("unable to clone repository %s: %w", redact.URL(h.url), err)
+	}
+
+	return h.checkoutCommit(ctx, repo)
+}
+
+// checkoutCommit pins a freshly cloned worktree to the configured commit.
+// It is a no-op for branch and tag references, which are checked out by the
+// clone itself.
+func (h *GitHandler) checkoutCommit(ctx context.Context, repo *git.Repository) error {
+	if len(h.commit) == 0 {
+		return nil
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("unable to open worktree: %w", err)
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(h.commit)}); err != nil {
+		return errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("unable to check out commit %s of repository %s: %w", h.commit, redact.URL(h.url), err))
+	}
+
+	// The checkout may have moved the superproject away from the submodule
+	// commits the clone recursion fetched.
+	return h.updateSubmodules(ctx, worktree)
+}
+
+// cloneOptions are the shared options for clones of the configured
+// repository. Branches and tags are fetched as shallow single-reference
+// clones; a pinned commit needs the full history, since git cannot fetch an
+// arbitrary sha shallowly.
+func (h *GitHandler) cloneOptions() *git.CloneOptions {
+	options := &git.CloneOptions{
+		URL:             h.url,
+		InsecureSkipTLS: h.insecureSkipTLS,
+		CABundle:        h.caBundle,
+		ProxyOptions:    h.proxy,
+	}
+
+	if h.recurseSubmodules {
+		options.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+	}
+
+	switch {
+	case len(h.commit) > 0:
+	case len(h.tag) > 0:
+		options.ReferenceName = plumbing.NewTagReferenceName(h.tag)
+		options.SingleBranch = true
+		options.Depth = 1
+	default:
+		options.ReferenceName = plumbing.NewBranchReferenceName(h.branch)
+		options.SingleBranch = true
+		options.Depth = 1
+	}
+
+	options.Auth = h.auth
+
+	return options
+}
+
+// fetchAndReset brings the cached clone up to date with the target
+// reference and hard resets the worktree to it. Tags and commits are
+// immutable, so a clone already at the right revision skips the fetch
+// entirely.
+func (h *GitHandler) fetchAndReset(ctx context.Context, repo *git.Repository) error {
+	if len(h.commit) > 0 || len(h.tag) > 0 {
+		if head, err := repo.Head(); err == nil {
+			if hash, err := h.targetHash(repo); err == nil && head.Hash() == *hash {
+				return nil
+			}
+		}
+	}
+
+	options := &git.FetchOptions{
+		Force:           true,
+		InsecureSkipTLS: h.insecureSkipTLS,
+		CABundle:        h.caBundle,
+		ProxyOptions:    h.proxy,
+	}
+
+	switch {
+	case len(h.commit) > 0:
+		options.RefSpecs = []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*"}
+	case len(h.tag) > 0:
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/tags/%s:refs/tags/%s", h.tag, h.tag))}
+		options.Depth = 1
+	default:
+		options.RefSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", h.branch, h.branch))}
+		options.Depth = 1
+	}
+
+	options.Auth = h.auth
+
+	if err := repo.FetchContext(ctx, options); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("unable to fetch repository %s: %w", redact.URL(h.url), err)
+	}
+
+	hash, err := h.targetHash(repo)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("unable to open worktree: %w", err)
+	}
+
+	if err := worktree.Reset(&git.ResetOptions{Commit: *hash, Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("unable to reset to %s: %w", h.reference(), err)
+	}
+
+	return h.updateSubmodules(ctx, worktree)
+}
+
+// updateSubmodules initializes and updates the submodules of a worktree
+// when recurseSubmodules is enabled. The reset above may have moved the
+// superproject to a revision pointing at different submodule commits, so
+// the clone-time recursion alone is not enough.
+func (h *GitHandler) updateSubmodules(ctx context.Context, worktree *git.Worktree) error {
+	if !h.recurseSubmodules {
+		return nil
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return fmt.Errorf("unable to list submodules of repository %s: %w", redact.URL(h.url), err)
+	}
+
+	options := &git.SubmoduleUpdateOptions{
+		Init:              true,
+		Auth:              h.auth,
+		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+	}
+
+	if err := submodules.UpdateContext(ctx, options); err != nil {
+		return fmt.Errorf("unable to update submodules of repository %s: %w", redact.URL(h.url), err)
+	}
+
+	return nil
+}
+
+// targetHash resolves the configured reference to a commit hash inside the
+// given repository, following annotated tags to the commit they point at.
+func (h *GitHandler) targetHash(repo *git.Repository) (*plumbing.Hash, error) {
+	var revision string
+
+	switch {
+	case len(h.commit) > 0:
+		revision = h.commit
+	case len(h.tag) > 0:
+		revision = "refs/tags/" + h.tag
+	default:
+		revision = "refs/remotes/origin/" + h.branch
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %s: %w", h.reference(), err)
+	}
+
+	return hash, nil
+}
+
+// lookupDotenvKey returns the value of a dotenv (.env) entry. Lines have the
+// form KEY=value with an optional "export " prefix; # comments are skipped
+// and quoted values are unquoted.
+func lookupDotenvKey(content []byte, key string) (string, error) {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(name) != key {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') ||
+				(value[0] == '\'' && value[len(value)-1] == '\'') {
+				return value[1 : len(value)-1], nil
+			}
+		}
+
+		return value, nil
+	}
+
+	return "", fmt.Errorf("key %s not found", key)
+}
+
+// collectDotenvEntries returns every dotenv entry of the file, parsed with
+// the same rules as lookupDotenvKey; later duplicates win.
+func collectDotenvEntries(content []byte) map[string]string {
+	entries := map[string]string{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') ||
+				(value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		entries[strings.TrimSpace(name)] = value
+	}
+
+	return entries
+}
+
+// lookupPropertiesKey returns the value of a Java properties entry. Both "="
+// and ":" separators are accepted, comments start with # or !, and a
+// trailing backslash continues the value on the next line.
+func lookupPropertiesKey(content []byte, key string) (string, error) {
+	lines := strings.Split(string(content), "\n")
+
+	for index := 0; index < len(lines); index++ {
+		line := strings.TrimSpace(lines[index])
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		for strings.HasSuffix(line, "\\") && index+1 < len(lines) {
+			index++
+			line = strings.TrimSuffix(line, "\\") + strings.TrimSpace(lines[index])
+		}
+
+		separator := strings.IndexAny(line, "=:")
+		if separator < 0 {
+			continue
+		}
+
+		if strings.TrimSpace(line[:separator]) == key {
+			return strings.TrimSpace(line[separator+1:]), nil
+		}
+	}
+
+	return "", fmt.Errorf("key %s not found", key)
+}
+
+// collectPropertiesEntries returns every Java properties entry of the file,
+// parsed with the same rules as lookupPropertiesKey; later duplicates win.
+func collectPropertiesEntries(content []byte) map[string]string {
+	entries := map[string]string{}
+	lines := strings.Split(string(content), "\n")
+
+	for index := 0; index < len(lines); index++ {
+		line := strings.TrimSpace(lines[index])
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		for strings.HasSuffix(line, "\\") && index+1 < len(lines) {
+			index++
+			line = strings.TrimSuffix(line, "\\") + strings.TrimSpace(lines[index])
+		}
+
+		separator := strings.IndexAny(line, "=:")
+		if separator < 0 {
+			continue
+		}
+
+		entries[strings.TrimSpace(line[:separator])] = strings.TrimSpace(line[separator+1:])
+	}
+
+	return entries
+}
+
+// parseTOML parses the subset of TOML used by configuration files: table
+// headers (including dotted ones), key = value entries with basic or literal
+// strings, and # comments. Tables become nested maps, so a value inside
+// [section] is addressed as section.key like any other nested document.
+func parseTOML(content []byte) (map[string]interface{}, error) {
+	root := map[string]interface{}{}
+	current := root
+
+	for number, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = root
+
+			for _, part := range strings.Split(strings.TrimSpace(line[1:len(line)-1]), ".") {
+				child, ok := current[part].(map[string]interface{})
+				if !ok {
+					child = map[string]interface{}{}
+					current[part] = child
+				}
+
+				current = child
+			}
+
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid toml on line %d", number+1)
+		}
+
+		current[strings.TrimSpace(name)] = parseTOMLValue(strings.TrimSpace(value))
+	}
+
+	return root, nil
+}
+
+// parseTOMLValue unquotes one TOML scalar. Unquoted values lose a trailing
+// comment; quoted values are returned verbatim.
+func parseTOMLValue(raw string) string {
+	if len(raw) >= 2 {
+		if raw[0] == '"' && raw[len(raw)-1] == '"' {
+			if unquoted, err := strconv.Unquote(raw); err == nil {
+				return unquoted
+			}
+
+			return raw[1 : len(raw)-1]
+		}
+
+		if raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+			return raw[1 : len(raw)-1]
+		}
+	}
+
+	if comment := strings.Index(raw, "#"); comment >= 0 {
+		raw = strings.TrimSpace(raw[:comment])
+	}
+
+	return raw
+}
+
+// lookupKey walks a parsed YAML document following a dot separated path of
+// keys and returns the scalar value found at the end of the path.
+func lookupKey(values map[string]interface{}, key string) (string, error) {
+	parts := strings.Split(key, ".")
+	var current interface{} = values
+
+	for i, part := range parts {
+		m, ok := toStringMap(current)
+		if !ok {
+			return "", fmt.Errorf("key %s not found", strings.Join(parts[:i+1], "."))
+		}
+
+		current, ok = m[part]
+		if !ok {
+			return "", fmt.Errorf("key %s not found", strings.Join(parts[:i+1], "."))
+		}
+	}
+
+	switch current.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		return "", fmt.Errorf("key %s does not hold a scalar value", key)
+	}
+
+	return fmt.Sprintf("%v", current), nil
+}
+
+// toStringMap normalizes the map types produced by the YAML decoder.
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			out[fmt.Sprintf("%v", k)] = v
+		}
+		return out, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestResolveFilePath covers the repository-root escape checks: a regression
+// here turns the file lookup into a read primitive on the container
+// filesystem.
+func TestResolveFilePath(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	secret := filepath.Join(outside, "secret.yaml")
+	if err := os.WriteFile(secret, []byte("password: hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "config.yaml"), []byte("key: value\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A symlink committed to the repository pointing outside the clone, and
+	// one staying inside it.
+	if err := os.Symlink(secret, filepath.Join(root, "escape.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(filepath.Join(root, "config.yaml"), filepath.Join(root, "alias.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &GitHandler{url: "https://git.example.com/org/repo.git"}
+
+	for _, file := range []string{"config.yaml", "alias.yaml"} {
+		if _, err := h.resolveFilePath(root, file); err != nil {
+			t.Errorf("file %s inside the repository was rejected: %v", file, err)
+		}
+	}
+
+	// A missing file resolves to its would-be path; the caller reports it as
+	// not found.
+	if _, err := h.resolveFilePath(root, "missing.yaml"); err != nil {
+		t.Errorf("missing file was rejected instead of left to the caller: %v", err)
+	}
+
+	escapes := []struct {
+		name string
+		file string
+	}{
+		{"absolute path", secret},
+		{"dot-dot segments", filepath.Join("..", filepath.Base(outside), "secret.yaml")},
+		{"symlink outside the root", "escape.yaml"},
+	}
+
+	for _, escape := range escapes {
+		t.Run(escape.name, func(t *testing.T) {
+			if path, err := h.resolveFilePath(root, escape.file); err == nil {
+				t.Fatalf("file %s escaping the repository root was accepted as %s", escape.file, path)
+			}
+		})
+	}
+}
+
+// hostKeyPair generates an ssh host key for callback tests.
+func hostKeyPair(t *testing.T) cryptossh.PublicKey {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := cryptossh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// TestInMemoryHostKeyCallback covers the in-memory knownHosts matching used
+// under --no-plaintext-on-disk: plain and hashed host patterns accept the
+// right key, and wrong keys or unknown hosts are rejected.
+func TestInMemoryHostKeyCallback(t *testing.T) {
+	hostKey := hostKeyPair(t)
+	otherKey := hostKeyPair(t)
+
+	entries := knownhosts.Line([]string{"git.example.com"}, hostKey) + "\n" +
+		knownhosts.HashHostname("hashed.example.com") + " " + string(cryptossh.MarshalAuthorizedKey(hostKey))
+
+	callback, err := inMemoryHostKeyCallback([]byte(entries))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := callback("git.example.com:22", nil, hostKey); err != nil {
+		t.Errorf("matching host key rejected: %v", err)
+	}
+
+	if err := callback("hashed.example.com:22", nil, hostKey); err != nil {
+		t.Errorf("matching hashed host key rejected: %v", err)
+	}
+
+	if err := callback("git.example.com:22", nil, otherKey); err == nil {
+		t.Error("wrong host key accepted")
+	}
+
+	if err := callback("unknown.example.com:22", nil, hostKey); err == nil {
+		t.Error("unknown host accepted")
+	}
+
+	if _, err := inMemoryHostKeyCallback([]byte("not a knownhosts line")); err == nil {
+		t.Error("invalid knownHosts content accepted")
+	}
+}
+
+// TestBuildAuthKnownHostsInMemory verifies that --no-plaintext-on-disk keeps
+// the knownHosts entries out of temporary files: buildAuth must not stage a
+// file and still verify host keys.
+func TestBuildAuthKnownHostsInMemory(t *testing.T) {
+	SetNoPlaintextOnDisk(true)
+	defer SetNoPlaintextOnDisk(false)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	hostKey := hostKeyPair(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-config"},
+		Data: map[string][]byte{
+			gitKeySSHPrivateKey: privateKey,
+			gitKeyKnownHosts:    []byte(knownhosts.Line([]string{"git.example.com"}, hostKey)),
+		},
+	}
+
+	h := &GitHandler{url: "ssh://git@git.example.com/org/repo.git"}
+
+	if err := h.buildAuth(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(h.knownHostsPath) > 0 {
+		t.Errorf("knownHosts staged on disk at %s despite --no-plaintext-on-disk", h.knownHostsPath)
+	}
+}
+
+// TestGetDataInMemoryLeavesNoDiskClone verifies the --no-plaintext-on-disk
+// read path: values resolve through an in-memory clone and the on-disk clone
+// cache directory is never created.
+func TestGetDataInMemoryLeavesNoDiskClone(t *testing.T) {
+	SetNoPlaintextOnDisk(true)
+	defer SetNoPlaintextOnDisk(false)
+
+	remote := t.TempDir()
+
+	repo, err := git.PlainInit(remote, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(remote, "config.yaml"), []byte("app:\n  environment: production\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := worktree.Add("config.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+
+	if _, err := worktree.Commit("add config", &git.CommitOptions{Author: signature}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-config"},
+		Data: map[string][]byte{
+			gitKeyURL:    []byte(remote),
+			gitKeyBranch: []byte("master"),
+			gitKeyFile:   []byte("config.yaml"),
+		},
+	}
+
+	h, err := NewGitHandler(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := h.GetData(context.Background(), "app.environment")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value.Reveal() != "production" {
+		t.Errorf("resolved %q, want %q", value.Reveal(), "production")
+	}
+
+	if _, err := os.Stat(h.cacheDir()); !os.IsNotExist(err) {
+		t.Errorf("on-disk clone cache %s exists despite --no-plaintext-on-disk", h.cacheDir())
+	}
+}
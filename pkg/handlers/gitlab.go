@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the gitlab provider configuration.
+const (
+	gitlabKeyURL       = "url"
+	gitlabKeyToken     = "token"
+	gitlabKeyProjectID = "projectID"
+	gitlabKeyGroupID   = "groupID"
+)
+
+// gitlabDefaultURL is the hosted GitLab instance.
+const gitlabDefaultURL = "https://gitlab.com"
+
+func init() {
+	Register(GitLabProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewGitLabHandler(secret)
+	})
+}
+
+// GitLabHandler resolves placeholders of the form
+// {{ gitlab:<secret>:<VARIABLE> }} against the CI/CD variables of a GitLab
+// project or group, for teams keeping shared infrastructure configuration as
+// GitLab variables.
+type GitLabHandler struct {
+	client    *nethttp.Client
+	url       string
+	token     string
+	projectID string
+	groupID   string
+}
+
+// NewGitLabHandler builds a GitLabHandler from a provider Secret. Exactly
+// one of projectID or groupID selects where variables are read from.
+func NewGitLabHandler(secret *corev1.Secret) (*GitLabHandler, error) {
+	h := &GitLabHandler{
+		url:       string(secret.Data[gitlabKeyURL]),
+		token:     string(secret.Data[gitlabKeyToken]),
+		projectID: string(secret.Data[gitlabKeyProjectID]),
+		groupID:   string(secret.Data[gitlabKeyGroupID]),
+	}
+
+	if len(h.token) == 0 {
+		return nil, fmt.Errorf("gitlab provider secret %s is missing the token key", secret.Name)
+	}
+
+	if (len(h.projectID) == 0) == (len(h.groupID) == 0) {
+		return nil, fmt.Errorf("gitlab provider secret %s needs exactly one of the projectID or groupID keys", secret.Name)
+	}
+
+	if len(h.url) == 0 {
+		h.url = gitlabDefaultURL
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	h.client = client
+
+	return h, nil
+}
+
+// variableURL builds the API URL reading one project or group variable.
+func (h *GitLabHandler) variableURL(name string) string {
+	if len(h.projectID) > 0 {
+		return fmt.Sprintf("%s/api/v4/projects/%s/variables/%s", h.url, url.PathEscape(h.projectID), url.PathEscape(name))
+	}
+
+	return fmt.Sprintf("%s/api/v4/groups/%s/variables/%s", h.url, url.PathEscape(h.groupID), url.PathEscape(name))
+}
+
+// Check verifies the GitLab API accepts the token.
+func (h *GitLabHandler) Check(ctx context.Context) error {
+	headers := map[string]string{"PRIVATE-TOKEN": h.token}
+
+	status, _, err := restRequest(ctx, h.client, nethttp.MethodGet, h.url+"/api/v4/user", headers, nil)
+	if err != nil {
+		return err
+	}
+
+	if status >= 400 {
+		return fmt.Errorf("gitlab API rejected the token: status %d", status)
+	}
+
+	return nil
+}
+
+// GetData reads the CI/CD variable named data.
+func (h *GitLabHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	headers := map[string]string{"PRIVATE-TOKEN": h.token}
+
+	status, body, err := restRequest(ctx, h.client, nethttp.MethodGet, h.variableURL(data), headers, nil)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read gitlab variable %s: status %d", data, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("gitlab variable %s not found", data))
+	case status >= 400:
+		return none, fmt.Errorf("unable to read gitlab variable %s: status %d", data, status)
+	}
+
+	var response struct {
+		Value string `json:"value"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return none, fmt.Errorf("unable to parse gitlab response for %s: %w", data, err)
+	}
+
+	return redact.Sensitive(response.Value), nil
+}
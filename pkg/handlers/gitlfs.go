@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// lfsPointerPrefix opens every Git LFS pointer file.
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/v1"
+
+// lfsTimeout bounds one LFS object download.
+const lfsTimeout = 60 * time.Second
+
+// resolveLFS passes file content through unless LFS resolution is enabled
+// and the content is a pointer blob, in which case the real object replaces
+// it.
+func (h *GitHandler) resolveLFS(ctx context.Context, content []byte, file string) ([]byte, error) {
+	if !h.lfs || !isLFSPointer(content) {
+		return content, nil
+	}
+
+	return h.fetchLFSObject(ctx, content, file)
+}
+
+// isLFSPointer reports whether content is a Git LFS pointer blob rather
+// than the real file.
+func isLFSPointer(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(lfsPointerPrefix))
+}
+
+// parseLFSPointer extracts the object id and size of the real object from a
+// pointer blob.
+func parseLFSPointer(pointer []byte) (oid string, size string, err error) {
+	for _, line := range strings.Split(string(pointer), "\n") {
+		if rest, ok := strings.CutPrefix(line, "oid sha256:"); ok {
+			oid = strings.TrimSpace(rest)
+		}
+
+		if rest, ok := strings.CutPrefix(line, "size "); ok {
+			size = strings.TrimSpace(rest)
+		}
+	}
+
+	if len(oid) == 0 || len(size) == 0 {
+		return "", "", fmt.Errorf("invalid git-lfs pointer")
+	}
+
+	return oid, size, nil
+}
+
+// lfsEndpoint derives the LFS API endpoint from the clone URL, which must
+// be HTTP(S); LFS over SSH needs a separate auth exchange the handler does
+// not speak.
+func (h *GitHandler) lfsEndpoint() (string, error) {
+	if !strings.HasPrefix(h.url, "http://") && !strings.HasPrefix(h.url, "https://") {
+		return "", fmt.Errorf("git-lfs objects of repository %s need an http(s) clone URL", redact.URL(h.url))
+	}
+
+	endpoint := strings.TrimSuffix(h.url, "/")
+
+	if !strings.HasSuffix(endpoint, ".git") {
+		endpoint += ".git"
+	}
+
+	return endpoint + "/info/lfs", nil
+}
+
+// fetchLFSObject downloads the real object behind a pointer blob through
+// the LFS batch API and verifies its digest against the pointer.
+func (h *GitHandler) fetchLFSObject(ctx context.Context, pointer []byte, file string) ([]byte, error) {
+	oid, size, err := parseLFSPointer(pointer)
+	if err != nil {
+		return nil, fmt.Errorf("file %s from repository %s: %w", file, redact.URL(h.url), err)
+	}
+
+	endpoint, err := h.lfsEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"operation": "download",
+		"transfers": []string{"basic"},
+		"objects":   []map[string]interface{}{{"oid": oid, "size": json.Number(size)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		"Accept":       "application/vnd.git-lfs+json",
+		"Content-Type": "application/vnd.git-lfs+json",
+	}
+
+	// The minted GitHub App token ends up in h.auth as well, so LFS shares
+	// whatever HTTP credentials the clone uses.
+	if basic, ok := h.auth.(*http.BasicAuth); ok {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(basic.Username+":"+basic.Password))
+	}
+
+	status, response, err := restRequest(ctx, h.lfsClient, nethttp.MethodPost, endpoint+"/objects/batch", headers, body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return nil, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("git-lfs batch request for %s rejected: status %d", file, status))
+	case status >= 400:
+		return nil, fmt.Errorf("git-lfs batch request for %s failed: status %d", file, status)
+	}
+
+	var batch struct {
+		Objects []struct {
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+			Actions struct {
+				Download struct {
+					Href   string            `json:"href"`
+					Header map[string]string `json:"header"`
+				} `json:"download"`
+			} `json:"actions"`
+		} `json:"objects"`
+	}
+
+	if err := json.Unmarshal(response, &batch); err != nil {
+		return nil, fmt.Errorf("unable to parse git-lfs batch response for %s: %w", file, err)
+	}
+
+	if len(batch.Objects) == 0 {
+		return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("git-lfs object of %s not found", file))
+	}
+
+	object := batch.Objects[0]
+
+	if object.Error != nil {
+		return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("git-lfs object of %s: %s", file, object.Error.Message))
+	}
+
+	if len(object.Actions.Download.Href) == 0 {
+		return nil, fmt.Errorf("git-lfs batch response for %s carries no download action", file)
+	}
+
+	status, content, err := restRequest(ctx, h.lfsClient, nethttp.MethodGet, object.Actions.Download.Href, object.Actions.Download.Header, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if status >= 400 {
+		return nil, fmt.Errorf("git-lfs download of %s failed: status %d", file, status)
+	}
+
+	digest := sha256.Sum256(content)
+
+	if hex.EncodeToString(digest[:]) != oid {
+		return nil, fmt.Errorf("git-lfs object of %s does not match the pointer digest", file)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Provider types supported in placeholders.
+const (
+	VaultProvider = "vault"
+	GitProvider   = "git"
+	SSMProvider   = "ssm"
+	GCPSMProvider = "gcpsm"
+
+	// K8sSecretProvider copies values from existing in-cluster Secrets. It is
+	// only available to callers with a cluster connection.
+	K8sSecretProvider = "k8ssecret"
+
+	// ConfigMapProvider reads non-sensitive values from an in-cluster
+	// ConfigMap. It is only available to callers with a cluster connection.
+	ConfigMapProvider = "configmap"
+
+	// HTTPProvider fetches values from a generic HTTP/REST endpoint.
+	HTTPProvider = "http"
+
+	ConsulProvider = "consul"
+	EtcdProvider   = "etcd"
+	S3Provider     = "s3"
+	ConjurProvider = "conjur"
+
+	OnePasswordProvider = "onepassword"
+	DopplerProvider     = "doppler"
+	AkeylessProvider    = "akeyless"
+	InfisicalProvider   = "infisical"
+	GitLabProvider      = "gitlab"
+
+	// PluginProvider forwards resolution to an external plugin process.
+	PluginProvider = "plugin"
+)
+
+// Factory builds a Handler from a provider configuration Secret. The client
+// is only used by providers reading from the cluster and may be nil.
+type Factory func(c client.Client, secret *corev1.Secret) (Handler, error)
+
+// registry maps provider types to their factories. Built-in providers
+// register themselves at package load; out-of-tree providers register from
+// the main package of a shim binary.
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes a provider type available to placeholders. It panics when
+// the type is already taken, which is a programming error.
+func Register(provider string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[provider]; exists {
+		panic(fmt.Sprintf("provider %q registered twice", provider))
+	}
+
+	registry[provider] = factory
+}
+
+// KnownProviders lists the registered provider types in sorted order, for
+// tooling that validates manifests without resolving them.
+func KnownProviders() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	providers := make([]string, 0, len(registry))
+
+	for provider := range registry {
+		providers = append(providers, provider)
+	}
+
+	sort.Strings(providers)
+
+	return providers
+}
+
+// forbidInsecureTLS globally rejects provider configurations that disable
+// TLS certificate verification. Set from the --forbid-insecure-tls flag for
+// regulated environments.
+var forbidInsecureTLS bool
+
+// SetForbidInsecureTLS configures whether provider Secrets may opt out of
+// TLS certificate verification.
+func SetForbidInsecureTLS(forbid bool) {
+	forbidInsecureTLS = forbid
+}
+
+// checkInsecureTLSAllowed rejects an insecure TLS request from the named
+// provider Secret when the operator forbids them. FIPS mode implies the
+// prohibition: an unverified connection cannot meet FIPS requirements.
+func checkInsecureTLSAllowed(secretName string) error {
+	if !forbidInsecureTLS && !fipsMode {
+		return nil
+	}
+
+	return errclass.Wrap(errclass.ProviderForbidden,
+		fmt.Errorf("provider secret %s requests insecureSkipTLS, which this operator forbids (--forbid-insecure-tls or --fips-mode)", secretName))
+}
+
+// Handler resolves the data portion of a placeholder against an external
+// provider. Resolved values travel as redact.SensitiveString so they cannot
+// be serialized into logs, events or status by accident.
+type Handler interface {
+	// GetData resolves data (the third field of a placeholder) to its value.
+	GetData(ctx context.Context, data string) (redact.SensitiveString, error)
+}
+
+// Checker is implemented by handlers that can verify connectivity to their
+// backing provider without reading any data.
+type Checker interface {
+	// Check returns an error when the provider is unreachable.
+	Check(ctx context.Context) error
+}
+
+// LeaseHolder is implemented by handlers whose resolved values expire, such
+// as dynamic secret engines. NextRotation reports when the earliest held
+// lease runs out, so callers can re-render before credentials rotate.
+type LeaseHolder interface {
+	NextRotation() (time.Time, bool)
+}
+
+// RenewalReporter is implemented by handlers that keep their own credentials
+// alive in the background. RenewalError returns the error of the last failed
+// renewal, or nil while renewal is healthy, so reconciles can surface a
+// degrading provider before its credentials actually stop working.
+type RenewalReporter interface {
+	RenewalError() error
+}
+
+// Closer is implemented by handlers holding background resources, such as a
+// token renewer goroutine. Close releases them when the handler is evicted
+// from the pool because its provider Secret changed.
+type Closer interface {
+	Close()
+}
+
+// GetHandler builds the Handler for a placeholder. The provider is configured
+// by a Secret named secretName in the given namespace. Callers on the hot
+// path should use a Pool instead, which reuses handler instances.
+func GetHandler(ctx context.Context, c client.Client, namespace, provider, secretName string) (Handler, error) {
+	secret := &corev1.Secret{}
+
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return nil, fmt.Errorf("unable to get provider secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	return newHandler(c, provider, secret)
+}
+
+// NewFromSecret builds the Handler for a provider type directly from a
+// configuration Secret that was not read from a cluster. Used by the dri CLI
+// to resolve placeholders against local provider configurations. Providers
+// that read from the cluster are unavailable through this path.
+func NewFromSecret(provider string, secret *corev1.Secret) (Handler, error) {
+	return newHandler(nil, provider, secret)
+}
+
+// newHandler builds the Handler for a provider type from its configuration
+// Secret through the registry. The client is only used by providers reading
+// from the cluster and may be nil for the rest.
+func newHandler(c client.Client, provider string, secret *corev1.Secret) (Handler, error) {
+	registryMu.RLock()
+	factory, ok := registry[provider]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+
+	handler, err := factory(c, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRateLimit(provider, handler), nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the http provider configuration.
+const (
+	httpKeyURL         = "url"
+	httpKeyBearerToken = "bearerToken"
+	httpKeyUsername    = "username"
+	httpKeyPassword    = "password"
+)
+
+func init() {
+	Register(HTTPProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewHTTPHandler(secret)
+	})
+}
+
+// HTTPHandler resolves placeholders of the form {{ http:<secret>:<key> }} by
+// fetching a URL and walking the parsed JSON or YAML response with a dot
+// separated path. The special key "." returns the raw response body, for
+// plain-text endpoints. Bearer-token and basic auth plus the shared TLS keys
+// (caCert, mTLS, spkiPin) are supported, so internal configuration services
+// behind a private CA can be used as data sources.
+type HTTPHandler struct {
+	url         string
+	bearerToken string
+	username    string
+	password    string
+
+	client *nethttp.Client
+}
+
+// NewHTTPHandler builds an HTTPHandler from a provider Secret.
+func NewHTTPHandler(secret *corev1.Secret) (*HTTPHandler, error) {
+	url := string(secret.Data[httpKeyURL])
+
+	if len(url) == 0 {
+		return nil, fmt.Errorf("http provider secret %s is missing the url key", secret.Name)
+	}
+
+	tlsConfig, err := buildTLSConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &nethttp.Client{Timeout: 30 * time.Second}
+
+	if tlsConfig != nil {
+		client.Transport = &nethttp.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	return &HTTPHandler{
+		url:         url,
+		bearerToken: string(secret.Data[httpKeyBearerToken]),
+		username:    string(secret.Data[httpKeyUsername]),
+		password:    string(secret.Data[httpKeyPassword]),
+		client:      client,
+	}, nil
+}
+
+// Check verifies the endpoint responds with a non-error status.
+func (h *HTTPHandler) Check(ctx context.Context) error {
+	_, err := h.fetch(ctx)
+
+	return err
+}
+
+// fetch performs one authenticated GET of the configured URL.
+func (h *HTTPHandler) fetch(ctx context.Context) ([]byte, error) {
+	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, h.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build request for %s: %w", redact.URL(h.url), err)
+	}
+
+	if len(h.bearerToken) > 0 {
+		request.Header.Set("Authorization", "Bearer "+h.bearerToken)
+	} else if len(h.username) > 0 || len(h.password) > 0 {
+		request.SetBasicAuth(h.username, h.password)
+	}
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", redact.URL(h.url), err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response from %s: %w", redact.URL(h.url), err)
+	}
+
+	switch {
+	case response.StatusCode == nethttp.StatusUnauthorized || response.StatusCode == nethttp.StatusForbidden:
+		return nil, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("%s returned status %d", redact.URL(h.url), response.StatusCode))
+	case response.StatusCode == nethttp.StatusNotFound:
+		return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("%s returned status %d", redact.URL(h.url), response.StatusCode))
+	case response.StatusCode >= 400:
+		return nil, fmt.Errorf("%s returned status %d", redact.URL(h.url), response.StatusCode)
+	}
+
+	return body, nil
+}
+
+// GetData fetches the configured URL and returns the value at the dot
+// separated path data. JSON responses parse as the YAML superset.
+func (h *HTTPHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	body, err := h.fetch(ctx)
+	if err != nil {
+		return none, err
+	}
+
+	if data == "." {
+		return redact.Sensitive(strings.TrimSpace(string(body))), nil
+	}
+
+	values := map[string]interface{}{}
+
+	if err := yaml.Unmarshal(body, &values); err != nil {
+		return none, fmt.Errorf("unable to parse response from %s: %w", redact.URL(h.url), err)
+	}
+
+	value, err := lookupKey(values, data)
+	if err != nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("%w in response from %s", err, redact.URL(h.url)))
+	}
+
+	return redact.Sensitive(value), nil
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the infisical provider configuration.
+const (
+	infisicalKeyToken       = "token"
+	infisicalKeyWorkspaceID = "workspaceID"
+	infisicalKeyAPIURL      = "apiURL"
+)
+
+// infisicalDefaultAPIURL is the hosted Infisical API.
+const infisicalDefaultAPIURL = "https://app.infisical.com"
+
+func init() {
+	Register(InfisicalProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewInfisicalHandler(secret)
+	})
+}
+
+// InfisicalHandler resolves placeholders of the form
+// {{ infisical:<secret>:<environment>@<SECRET_KEY> }} against an Infisical
+// workspace using a machine-identity access token.
+type InfisicalHandler struct {
+	client      *nethttp.Client
+	apiURL      string
+	token       string
+	workspaceID string
+}
+
+// NewInfisicalHandler builds an InfisicalHandler from a provider Secret.
+func NewInfisicalHandler(secret *corev1.Secret) (*InfisicalHandler, error) {
+	h := &InfisicalHandler{
+		apiURL:      string(secret.Data[infisicalKeyAPIURL]),
+		token:       string(secret.Data[infisicalKeyToken]),
+		workspaceID: string(secret.Data[infisicalKeyWorkspaceID]),
+	}
+
+	if len(h.token) == 0 {
+		return nil, fmt.Errorf("infisical provider secret %s is missing the token key", secret.Name)
+	}
+
+	if len(h.workspaceID) == 0 {
+		return nil, fmt.Errorf("infisical provider secret %s is missing the workspaceID key", secret.Name)
+	}
+
+	if len(h.apiURL) == 0 {
+		h.apiURL = infisicalDefaultAPIURL
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	h.client = client
+
+	return h, nil
+}
+
+// GetData reads the secret addressed as <environment>@<SECRET_KEY> from the
+// configured workspace.
+func (h *InfisicalHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	environment, secretKey, found := strings.Cut(data, "@")
+	if !found {
+		return none, fmt.Errorf("infisical placeholder data %q must have the form <environment>@<SECRET_KEY>", data)
+	}
+
+	query := url.Values{
+		"environment": {environment},
+		"workspaceId": {h.workspaceID},
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v3/secrets/raw/%s?%s", h.apiURL, url.PathEscape(secretKey), query.Encode())
+	headers := map[string]string{"Authorization": "Bearer " + h.token}
+
+	status, body, err := restRequest(ctx, h.client, nethttp.MethodGet, requestURL, headers, nil)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read infisical secret %s: status %d", data, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("infisical secret %s not found", data))
+	case status >= 400:
+		return none, fmt.Errorf("unable to read infisical secret %s: status %d", data, status)
+	}
+
+	var response struct {
+		Secret struct {
+			SecretValue string `json:"secretValue"`
+		} `json:"secret"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return none, fmt.Errorf("unable to parse infisical response for %s: %w", data, err)
+	}
+
+	return redact.Sensitive(response.Secret.SecretValue), nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the k8ssecret provider configuration.
+const (
+	k8ssecretKeyAllowedNamespaces = "allowedNamespaces"
+)
+
+func init() {
+	Register(K8sSecretProvider, func(c client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewK8sSecretHandler(c, secret)
+	})
+}
+
+// K8sSecretHandler resolves placeholders of the form
+// {{ k8ssecret:<secret>:<namespace>/<name>#<key> }} by copying a value from
+// an existing in-cluster Secret, possibly outside the resource namespace.
+// Cross-namespace reads are gated by an explicit allow-list in the provider
+// Secret, so a tenant cannot lift Secrets from namespaces the cluster
+// administrator did not open up.
+type K8sSecretHandler struct {
+	client client.Client
+
+	// allowedNamespaces are the namespaces readable through this provider
+	// Secret. "*" allows every namespace.
+	allowedNamespaces []string
+}
+
+// NewK8sSecretHandler builds a K8sSecretHandler from a provider Secret. The
+// client is the cluster connection of the caller; local tooling without one
+// cannot use this provider.
+func NewK8sSecretHandler(c client.Client, secret *corev1.Secret) (*K8sSecretHandler, error) {
+	if c == nil {
+		return nil, fmt.Errorf("the k8ssecret provider requires a cluster connection")
+	}
+
+	allowed := string(secret.Data[k8ssecretKeyAllowedNamespaces])
+
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("k8ssecret provider secret %s is missing the allowedNamespaces key", secret.Name)
+	}
+
+	var namespaces []string
+
+	for _, namespace := range strings.Split(allowed, ",") {
+		namespaces = append(namespaces, strings.TrimSpace(namespace))
+	}
+
+	return &K8sSecretHandler{client: c, allowedNamespaces: namespaces}, nil
+}
+
+// allowsNamespace reports whether the allow-list covers namespace.
+func (h *K8sSecretHandler) allowsNamespace(namespace string) bool {
+	for _, allowed := range h.allowedNamespaces {
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetData reads the key from the Secret named by data, of the form
+// <namespace>/<name>#<key>.
+func (h *K8sSecretHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	parts := strings.SplitN(data, "#", 2)
+
+	if len(parts) != 2 {
+		return none, fmt.Errorf("k8ssecret placeholder data %q must have the form <namespace>/<name>#<key>", data)
+	}
+
+	ref := strings.SplitN(parts[0], "/", 2)
+
+	if len(ref) != 2 {
+		return none, fmt.Errorf("k8ssecret placeholder data %q must have the form <namespace>/<name>#<key>", data)
+	}
+
+	namespace := ref[0]
+	name := ref[1]
+	key := parts[1]
+
+	if !h.allowsNamespace(namespace) {
+		return none, errclass.Wrap(errclass.ProviderForbidden,
+			fmt.Errorf("namespace %s is not in the allowedNamespaces of this provider secret", namespace))
+	}
+
+	secret := &corev1.Secret{}
+
+	if err := h.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("secret %s/%s not found", namespace, name))
+		}
+
+		return none, fmt.Errorf("unable to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found in secret %s/%s", key, namespace, name))
+	}
+
+	return redact.Sensitive(string(value)), nil
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/metrics"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// negativeCacheName is the cache label used in cache metrics.
+const negativeCacheName = "negative"
+
+// negativeTTL is how long a "key not found" result is remembered. Short, so
+// a key created after the fact is picked up on the next retry tick.
+const negativeTTL = 30 * time.Second
+
+// negativeCache remembers recent KeyNotFound results so a resource
+// referencing a nonexistent key doesn't hammer the provider on every retry.
+type negativeCache struct {
+	mu      sync.Mutex
+	entries map[string]negativeEntry
+}
+
+type negativeEntry struct {
+	err     error
+	expires time.Time
+}
+
+var missingKeys = &negativeCache{entries: map[string]negativeEntry{}}
+
+func (c *negativeCache) get(key string) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		metrics.CacheEntries.WithLabelValues(negativeCacheName).Set(float64(len(c.entries)))
+		return nil, false
+	}
+
+	return entry.err, true
+}
+
+func (c *negativeCache) put(key string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = negativeEntry{err: err, expires: time.Now().Add(negativeTTL)}
+	metrics.CacheEntries.WithLabelValues(negativeCacheName).Set(float64(len(c.entries)))
+}
+
+// withNegativeCache wraps a handler so KeyNotFound results are served from
+// the negative cache until their TTL expires. prefix scopes cache keys to one
+// provider configuration.
+func withNegativeCache(prefix string, handler Handler) Handler {
+	return &negCachedHandler{prefix: prefix, handler: handler}
+}
+
+type negCachedHandler struct {
+	prefix  string
+	handler Handler
+}
+
+func (h *negCachedHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	key := h.prefix + "|" + data
+
+	if err, ok := missingKeys.get(key); ok {
+		metrics.CacheHits.WithLabelValues(negativeCacheName).Inc()
+		return redact.SensitiveString{}, err
+	}
+
+	value, err := h.handler.GetData(ctx, data)
+
+	if err != nil && errclass.Classify(err) == errclass.KeyNotFound {
+		metrics.CacheMisses.WithLabelValues(negativeCacheName).Inc()
+		missingKeys.put(key, err)
+	}
+
+	return value, err
+}
+
+// NextRotation delegates to the wrapped handler when it holds leases.
+func (h *negCachedHandler) NextRotation() (time.Time, bool) {
+	if holder, ok := h.handler.(LeaseHolder); ok {
+		return holder.NextRotation()
+	}
+
+	return time.Time{}, false
+}
+
+// RenewalError delegates to the wrapped handler when it renews credentials.
+func (h *negCachedHandler) RenewalError() error {
+	if reporter, ok := h.handler.(RenewalReporter); ok {
+		return reporter.RenewalError()
+	}
+
+	return nil
+}
+
+// Close delegates to the wrapped handler when it holds background
+// resources.
+func (h *negCachedHandler) Close() {
+	if closer, ok := h.handler.(Closer); ok {
+		closer.Close()
+	}
+}
+
+// Check delegates to the wrapped handler when it supports connectivity
+// checks.
+func (h *negCachedHandler) Check(ctx context.Context) error {
+	if checker, ok := h.handler.(Checker); ok {
+		return checker.Check(ctx)
+	}
+
+	return nil
+}
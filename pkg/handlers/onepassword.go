@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the onepassword provider configuration.
+const (
+	onepasswordKeyHost  = "host"
+	onepasswordKeyToken = "token"
+)
+
+func init() {
+	Register(OnePasswordProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewOnePasswordHandler(secret)
+	})
+}
+
+// OnePasswordHandler resolves placeholders of the form
+// {{ onepassword:<secret>:<vault>/<item>@<field> }} against a 1Password
+// Connect server. Vault and item may be given by title or by id; the field
+// is matched by label or id.
+type OnePasswordHandler struct {
+	client *nethttp.Client
+	host   string
+	token  string
+}
+
+// NewOnePasswordHandler builds a OnePasswordHandler from a provider Secret.
+func NewOnePasswordHandler(secret *corev1.Secret) (*OnePasswordHandler, error) {
+	host := string(secret.Data[onepasswordKeyHost])
+	token := string(secret.Data[onepasswordKeyToken])
+
+	if len(host) == 0 {
+		return nil, fmt.Errorf("onepassword provider secret %s is missing the host key", secret.Name)
+	}
+
+	if len(token) == 0 {
+		return nil, fmt.Errorf("onepassword provider secret %s is missing the token key", secret.Name)
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OnePasswordHandler{client: client, host: host, token: token}, nil
+}
+
+// get performs one authenticated GET against the Connect API and decodes the
+// JSON response into out.
+func (h *OnePasswordHandler) get(ctx context.Context, path string, out interface{}) error {
+	headers := map[string]string{"Authorization": "Bearer " + h.token}
+
+	status, data, err := restRequest(ctx, h.client, nethttp.MethodGet, h.host+path, headers, nil)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("connect server rejected the token: status %d", status))
+	case status == nethttp.StatusNotFound:
+		return errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("connect resource %s not found", path))
+	case status >= 400:
+		return fmt.Errorf("connect server returned status %d for %s", status, path)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unable to parse connect response for %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Check verifies the Connect server accepts the token by listing vaults.
+func (h *OnePasswordHandler) Check(ctx context.Context) error {
+	var vaults []struct{}
+
+	return h.get(ctx, "/v1/vaults", &vaults)
+}
+
+// resolveVault returns the id of a vault referenced by title or id.
+func (h *OnePasswordHandler) resolveVault(ctx context.Context, reference string) (string, error) {
+	var vaults []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+
+	filter := url.QueryEscape(fmt.Sprintf("title eq %q", reference))
+
+	if err := h.get(ctx, "/v1/vaults?filter="+filter, &vaults); err != nil {
+		return "", err
+	}
+
+	if len(vaults) > 0 {
+		return vaults[0].ID, nil
+	}
+
+	// No title match: treat the reference as a vault id.
+	return reference, nil
+}
+
+// GetData reads a field of a Connect item addressed as
+// <vault>/<item>@<field>.
+func (h *OnePasswordHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	location, field, found := strings.Cut(data, "@")
+	if !found {
+		return none, fmt.Errorf("onepassword placeholder data %q must have the form <vault>/<item>@<field>", data)
+	}
+
+	vaultRef, itemRef, found := strings.Cut(location, "/")
+	if !found {
+		return none, fmt.Errorf("onepassword placeholder data %q must have the form <vault>/<item>@<field>", data)
+	}
+
+	vaultID, err := h.resolveVault(ctx, vaultRef)
+	if err != nil {
+		return none, err
+	}
+
+	var items []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+
+	filter := url.QueryEscape(fmt.Sprintf("title eq %q", itemRef))
+
+	if err := h.get(ctx, fmt.Sprintf("/v1/vaults/%s/items?filter=%s", url.PathEscape(vaultID), filter), &items); err != nil {
+		return none, err
+	}
+
+	itemID := itemRef
+
+	if len(items) > 0 {
+		itemID = items[0].ID
+	}
+
+	var item struct {
+		Fields []struct {
+			ID    string `json:"id"`
+			Label string `json:"label"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	}
+
+	if err := h.get(ctx, fmt.Sprintf("/v1/vaults/%s/items/%s", url.PathEscape(vaultID), url.PathEscape(itemID)), &item); err != nil {
+		return none, err
+	}
+
+	for _, candidate := range item.Fields {
+		if candidate.Label == field || candidate.ID == field {
+			return redact.Sensitive(candidate.Value), nil
+		}
+	}
+
+	return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("field %s not found in item %s of vault %s", field, itemRef, vaultRef))
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the plugin provider configuration.
+const (
+	pluginKeyEndpoint = "endpoint"
+	pluginKeyToken    = "token"
+)
+
+func init() {
+	Register(PluginProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewPluginHandler(secret)
+	})
+}
+
+// PluginHandler resolves placeholders of the form {{ plugin:<secret>:<data> }}
+// by forwarding the data portion to an external provider plugin, so backends
+// without a compiled-in handler can be served by a small out-of-process shim
+// in any language. The plugin listens on a Unix socket (endpoint
+// unix:///path.sock, typically a sidecar sharing an emptyDir) or an HTTP
+// endpoint, and speaks a two-call JSON protocol:
+//
+//	POST /v1/resolve {"data": "<data>"}  -> 200 {"value": "<value>"}
+//	GET  /v1/healthz                     -> 200
+//
+// The plugin signals a missing key with 404 and an authentication problem
+// with 401 or 403; those map onto the usual error classes.
+type PluginHandler struct {
+	client  *nethttp.Client
+	baseURL string
+	token   string
+}
+
+// NewPluginHandler builds a PluginHandler from a provider Secret.
+func NewPluginHandler(secret *corev1.Secret) (*PluginHandler, error) {
+	endpoint := string(secret.Data[pluginKeyEndpoint])
+
+	if len(endpoint) == 0 {
+		return nil, fmt.Errorf("plugin provider secret %s is missing the endpoint key", secret.Name)
+	}
+
+	h := &PluginHandler{token: string(secret.Data[pluginKeyToken])}
+
+	if socketPath, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		// Requests carry a placeholder host; the transport dials the socket.
+		h.baseURL = "http://plugin"
+		h.client = &nethttp.Client{
+			Timeout: 30 * time.Second,
+			Transport: &nethttp.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		}
+
+		return h, nil
+	}
+
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("plugin provider secret %s has an invalid endpoint; expected unix://, http:// or https://", secret.Name)
+	}
+
+	client, err := newRESTClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	h.baseURL = strings.TrimSuffix(endpoint, "/")
+	h.client = client
+
+	return h, nil
+}
+
+// headers builds the request headers, carrying the optional bearer token.
+func (h *PluginHandler) headers() map[string]string {
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	if len(h.token) > 0 {
+		headers["Authorization"] = "Bearer " + h.token
+	}
+
+	return headers
+}
+
+// Check verifies the plugin responds on its health endpoint.
+func (h *PluginHandler) Check(ctx context.Context) error {
+	status, _, err := restRequest(ctx, h.client, nethttp.MethodGet, h.baseURL+"/v1/healthz", h.headers(), nil)
+	if err != nil {
+		return err
+	}
+
+	if status >= 400 {
+		return fmt.Errorf("plugin health check returned status %d", status)
+	}
+
+	return nil
+}
+
+// GetData forwards the placeholder data to the plugin and returns the
+// resolved value.
+func (h *PluginHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	body, err := json.Marshal(map[string]string{"data": data})
+	if err != nil {
+		return none, err
+	}
+
+	status, response, err := restRequest(ctx, h.client, nethttp.MethodPost, h.baseURL+"/v1/resolve", h.headers(), body)
+	if err != nil {
+		return none, err
+	}
+
+	switch {
+	case status == nethttp.StatusUnauthorized || status == nethttp.StatusForbidden:
+		return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("plugin rejected the request for %s: status %d", data, status))
+	case status == nethttp.StatusNotFound:
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("plugin has no value for %s", data))
+	case status >= 400:
+		return none, fmt.Errorf("plugin returned status %d for %s", status, data)
+	}
+
+	var result struct {
+		Value *string `json:"value"`
+	}
+
+	if err := json.Unmarshal(response, &result); err != nil {
+		return none, fmt.Errorf("unable to parse plugin response for %s: %w", data, err)
+	}
+
+	if result.Value == nil {
+		return none, fmt.Errorf("plugin response for %s carries no value", data)
+	}
+
+	return redact.Sensitive(*result.Value), nil
+}
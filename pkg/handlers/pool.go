@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/metrics"
+)
+
+// poolCache is the cache label used in cache metrics.
+const poolCache = "client"
+
+// Pool caches Handler instances per provider configuration Secret so Vault
+// clients, git auth objects and HTTP transports are built once and reused
+// across reconciles. An entry is invalidated when the resourceVersion of its
+// Secret changes.
+type Pool struct {
+	client client.Client
+
+	mu      sync.Mutex
+	entries map[string]poolEntry
+}
+
+type poolEntry struct {
+	handler         Handler
+	resourceVersion string
+}
+
+// NewPool builds an empty Pool reading provider Secrets through c.
+func NewPool(c client.Client) *Pool {
+	return &Pool{
+		client:  c,
+		entries: map[string]poolEntry{},
+	}
+}
+
+// Get returns the Handler for a placeholder, reusing a pooled instance when
+// the provider Secret has not changed since it was built. The second return
+// reports whether the handler came from the pool, so callers can count cache
+// hits per reconcile.
+func (p *Pool) Get(ctx context.Context, namespace, provider, secretName string) (Handler, bool, error) {
+	secret := &corev1.Secret{}
+
+	if err := p.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return nil, false, fmt.Errorf("unable to get provider secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", provider, namespace, secretName)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.entries[key]; ok && entry.resourceVersion == secret.ResourceVersion {
+		metrics.CacheHits.WithLabelValues(poolCache).Inc()
+		return entry.handler, true, nil
+	}
+
+	metrics.CacheMisses.WithLabelValues(poolCache).Inc()
+
+	// A stale entry is replaced below; release its background resources
+	// (token renewers and the like) so changed Secrets do not leak them.
+	if entry, ok := p.entries[key]; ok {
+		if closer, ok := entry.handler.(Closer); ok {
+			closer.Close()
+		}
+	}
+
+	handler, err := newHandler(p.client, provider, secret)
+	if err != nil {
+		return nil, false, err
+	}
+
+	handler = withNegativeCache(key, handler)
+
+	p.entries[key] = poolEntry{handler: handler, resourceVersion: secret.ResourceVersion}
+	metrics.CacheEntries.WithLabelValues(poolCache).Set(float64(len(p.entries)))
+
+	return handler, false, nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// rateLimiters holds one token bucket per provider type. Providers without
+// an entry are not limited.
+var rateLimiters = struct {
+	sync.RWMutex
+	m map[string]*rate.Limiter
+}{m: map[string]*rate.Limiter{}}
+
+// SetRateLimits configures per-provider client-side rate limits from a comma
+// separated list such as "vault=10,git=2", where the number is the allowed
+// calls per second (burst equals the rate). A rate of 0 removes the limit.
+func SetRateLimits(spec string) error {
+	if len(spec) == 0 {
+		return nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid rate limit entry %q, expected <provider>=<calls-per-second>", entry)
+		}
+
+		provider := strings.TrimSpace(parts[0])
+
+		qps, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || qps < 0 {
+			return fmt.Errorf("invalid rate limit entry %q, expected <provider>=<calls-per-second>", entry)
+		}
+
+		rateLimiters.Lock()
+		if qps == 0 {
+			delete(rateLimiters.m, provider)
+		} else {
+			burst := int(qps)
+			if burst < 1 {
+				burst = 1
+			}
+
+			rateLimiters.m[provider] = rate.NewLimiter(rate.Limit(qps), burst)
+		}
+		rateLimiters.Unlock()
+	}
+
+	return nil
+}
+
+func limiterFor(provider string) *rate.Limiter {
+	rateLimiters.RLock()
+	defer rateLimiters.RUnlock()
+
+	return rateLimiters.m[provider]
+}
+
+// withRateLimit wraps a handler with the token bucket configured for its
+// provider type, if any.
+func withRateLimit(provider string, handler Handler) Handler {
+	if limiterFor(provider) == nil {
+		return handler
+	}
+
+	return &limitedHandler{provider: provider, handler: handler}
+}
+
+// limitedHandler waits for a rate limiter token before every provider call.
+type limitedHandler struct {
+	provider string
+	handler  Handler
+}
+
+func (l *limitedHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	if limiter := limiterFor(l.provider); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return redact.SensitiveString{}, fmt.Errorf("rate limiter wait for provider %s: %w", l.provider, err)
+		}
+	}
+
+	return l.handler.GetData(ctx, data)
+}
+
+// NextRotation delegates to the wrapped handler when it holds leases.
+func (l *limitedHandler) NextRotation() (time.Time, bool) {
+	if holder, ok := l.handler.(LeaseHolder); ok {
+		return holder.NextRotation()
+	}
+
+	return time.Time{}, false
+}
+
+// RenewalError delegates to the wrapped handler when it renews credentials.
+func (l *limitedHandler) RenewalError() error {
+	if reporter, ok := l.handler.(RenewalReporter); ok {
+		return reporter.RenewalError()
+	}
+
+	return nil
+}
+
+// Close delegates to the wrapped handler when it holds background
+// resources.
+func (l *limitedHandler) Close() {
+	if closer, ok := l.handler.(Closer); ok {
+		closer.Close()
+	}
+}
+
+// Check delegates to the wrapped handler when it supports connectivity
+// checks. Checks are not rate limited.
+func (l *limitedHandler) Check(ctx context.Context) error {
+	if checker, ok := l.handler.(Checker); ok {
+		return checker.Check(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/metrics"
+)
+
+// repoCacheName is the cache label used in cache metrics.
+const repoCacheName = "repo"
+
+// defaultRepoCacheBudget is the disk budget for cached clones: 1 GiB.
+const defaultRepoCacheBudget = int64(1 << 30)
+
+// repoCache tracks the disk usage of cached clones and evicts the least
+// recently used ones when the overall budget is exceeded, so many cached
+// repositories cannot push the pod into ephemeral-storage eviction.
+type repoCache struct {
+	mu      sync.Mutex
+	budget  int64
+	entries map[string]*repoCacheEntry
+}
+
+type repoCacheEntry struct {
+	dir      string
+	lockKey  string
+	size     int64
+	lastUsed time.Time
+}
+
+var cloneCache = &repoCache{
+	budget:  defaultRepoCacheBudget,
+	entries: map[string]*repoCacheEntry{},
+}
+
+// SetRepoCacheBudget configures the overall disk budget for cached clones in
+// bytes. Zero or negative keeps the default.
+func SetRepoCacheBudget(bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+
+	cloneCache.mu.Lock()
+	cloneCache.budget = bytes
+	cloneCache.mu.Unlock()
+}
+
+// touch records a use of the clone at dir, refreshes its measured size and
+// evicts least recently used clones while the budget is exceeded.
+func (c *repoCache) touch(dir, lockKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[dir]
+	if !ok {
+		entry = &repoCacheEntry{dir: dir, lockKey: lockKey}
+		c.entries[dir] = entry
+	}
+
+	entry.size = dirSize(dir)
+	entry.lastUsed = time.Now()
+
+	c.evictLocked()
+	c.publishLocked()
+}
+
+// evictLocked removes least recently used clones until total usage fits the
+// budget. Clones whose repository lock is currently held are skipped and
+// retried on the next touch.
+func (c *repoCache) evictLocked() {
+	for c.totalLocked() > c.budget && len(c.entries) > 1 {
+		var oldest *repoCacheEntry
+
+		for _, entry := range c.entries {
+			if oldest == nil || entry.lastUsed.Before(oldest.lastUsed) {
+				oldest = entry
+			}
+		}
+
+		lock, _ := repoLocks.LoadOrStore(oldest.lockKey, &sync.Mutex{})
+		mutex := lock.(*sync.Mutex)
+
+		if !mutex.TryLock() {
+			return
+		}
+
+		if err := os.RemoveAll(oldest.dir); err != nil {
+			ctrl.Log.WithName("repo-cache").Error(err, "unable to evict cached clone", "dir", oldest.dir)
+		}
+
+		mutex.Unlock()
+
+		delete(c.entries, oldest.dir)
+		metrics.CacheEvictions.WithLabelValues(repoCacheName).Inc()
+	}
+}
+
+func (c *repoCache) totalLocked() int64 {
+	var total int64
+
+	for _, entry := range c.entries {
+		total += entry.size
+	}
+
+	return total
+}
+
+func (c *repoCache) publishLocked() {
+	metrics.CacheEntries.WithLabelValues(repoCacheName).Set(float64(len(c.entries)))
+	metrics.RepoCacheBytes.Set(float64(c.totalLocked()))
+}
+
+// dirSize sums the file sizes under dir. Errors count as zero so cache
+// accounting never fails a render.
+func dirSize(dir string) int64 {
+	var size int64
+
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// newRESTClient builds the HTTP client shared by the REST-based providers,
+// honoring the shared TLS keys of the provider Secret.
+func newRESTClient(secret *corev1.Secret) (*nethttp.Client, error) {
+	tlsConfig, err := buildTLSConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &nethttp.Client{Timeout: 30 * time.Second}
+
+	if tlsConfig != nil {
+		client.Transport = &nethttp.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	return client, nil
+}
+
+// restRequest performs one HTTP request and returns the status code and
+// body. Transport errors mention only the redacted URL.
+func restRequest(ctx context.Context, client *nethttp.Client, method, url string, headers map[string]string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	request, err := nethttp.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to build request for %s: %w", redact.URL(url), err)
+	}
+
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to reach %s: %w", redact.URL(url), err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read response from %s: %w", redact.URL(url), err)
+	}
+
+	return response.StatusCode, data, nil
+}
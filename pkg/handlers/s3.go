@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the s3 provider configuration.
+const (
+	s3KeyBucket    = "bucket"
+	s3KeyObjectKey = "objectKey"
+	s3KeyEndpoint  = "endpoint"
+	s3KeyFileType  = "fileType"
+)
+
+func init() {
+	Register(S3Provider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewS3Handler(secret)
+	})
+}
+
+// S3Handler resolves placeholders of the form {{ s3:<secret>:<key> }} against
+// a configuration file stored in S3 or any S3-compatible endpoint such as
+// MinIO, analogous to the git handler's file-based lookup. The file type of
+// the object may be yaml (the default, also covering JSON) or ini.
+type S3Handler struct {
+	client    *s3.S3
+	bucket    string
+	objectKey string
+	fileType  string
+}
+
+// NewS3Handler builds an S3Handler from a provider Secret, using the shared
+// AWS session keys. A custom endpoint enables path-style addressing for
+// S3-compatible object stores.
+func NewS3Handler(secret *corev1.Secret) (*S3Handler, error) {
+	bucket := string(secret.Data[s3KeyBucket])
+	objectKey := string(secret.Data[s3KeyObjectKey])
+
+	if len(bucket) == 0 {
+		return nil, fmt.Errorf("s3 provider secret %s is missing the bucket key", secret.Name)
+	}
+
+	if len(objectKey) == 0 {
+		return nil, fmt.Errorf("s3 provider secret %s is missing the objectKey key", secret.Name)
+	}
+
+	fileType := string(secret.Data[s3KeyFileType])
+
+	switch fileType {
+	case "":
+		fileType = "yaml"
+	case "yaml", "json", "ini":
+	default:
+		return nil, fmt.Errorf("s3 provider secret %s has unsupported fileType %q", secret.Name, fileType)
+	}
+
+	config := aws.NewConfig()
+
+	if endpoint := string(secret.Data[s3KeyEndpoint]); len(endpoint) > 0 {
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+
+	sess, err := newAWSSession(secret, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Handler{
+		client:    s3.New(sess),
+		bucket:    bucket,
+		objectKey: objectKey,
+		fileType:  fileType,
+	}, nil
+}
+
+// Check verifies the configured object exists and is readable.
+func (h *S3Handler) Check(ctx context.Context) error {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(h.bucket),
+		Key:    aws.String(h.objectKey),
+	}
+
+	if _, err := h.client.HeadObjectWithContext(ctx, input); err != nil {
+		return fmt.Errorf("unable to access s3 object %s/%s: %w", h.bucket, h.objectKey, err)
+	}
+
+	return nil
+}
+
+// GetData downloads the configured object and returns the value at the key
+// data. YAML and JSON files use a dot separated path; ini files use
+// <section>.<key>, or the bare key for entries outside any section.
+func (h *S3Handler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(h.bucket),
+		Key:    aws.String(h.objectKey),
+	}
+
+	output, err := h.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) {
+			switch awsErr.Code() {
+			case s3.ErrCodeNoSuchKey, s3.ErrCodeNoSuchBucket:
+				return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("s3 object %s/%s not found", h.bucket, h.objectKey))
+			case "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch", "ExpiredToken":
+				return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read s3 object %s/%s: %w", h.bucket, h.objectKey, err))
+			}
+		}
+
+		return none, fmt.Errorf("unable to read s3 object %s/%s: %w", h.bucket, h.objectKey, err)
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		return none, fmt.Errorf("unable to read s3 object %s/%s: %w", h.bucket, h.objectKey, err)
+	}
+
+	var value string
+
+	switch h.fileType {
+	case "ini":
+		value, err = lookupINIKey(content, data)
+	default:
+		values := map[string]interface{}{}
+
+		if err = yaml.Unmarshal(content, &values); err != nil {
+			return none, fmt.Errorf("unable to parse s3 object %s/%s: %w", h.bucket, h.objectKey, err)
+		}
+
+		value, err = lookupKey(values, data)
+	}
+
+	if err != nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("%w in s3 object %s/%s", err, h.bucket, h.objectKey))
+	}
+
+	return redact.Sensitive(value), nil
+}
+
+// lookupINIKey returns the value of an ini entry addressed as
+// <section>.<key>, or the bare key for entries before any section header.
+func lookupINIKey(content []byte, key string) (string, error) {
+	wantSection := ""
+	wantKey := key
+
+	if dot := strings.Index(key, "."); dot >= 0 {
+		wantSection = key[:dot]
+		wantKey = key[dot+1:]
+	}
+
+	section := ""
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		if section == wantSection && strings.TrimSpace(name) == wantKey {
+			return strings.TrimSpace(value), nil
+		}
+	}
+
+	return "", fmt.Errorf("key %s not found", key)
+}
+
+// collectINIEntries returns every entry of one ini section (the empty
+// section covers entries before any header), parsed with the same rules as
+// lookupINIKey; later duplicates win.
+func collectINIEntries(content []byte, wantSection string) map[string]string {
+	entries := map[string]string{}
+	section := ""
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if len(line) == 0 || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found || section != wantSection {
+			continue
+		}
+
+		entries[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return entries
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import "fmt"
+
+// ConfigField describes one key of a provider configuration Secret.
+type ConfigField struct {
+	// Name is the Secret data key.
+	Name string
+
+	// Required marks keys the handler constructor rejects the Secret without.
+	Required bool
+
+	// Comment is a one-line description rendered above the key in scaffolded
+	// manifests.
+	Comment string
+}
+
+// tlsConfigFields are the optional TLS keys accepted by every provider that
+// goes through buildTLSConfig.
+func tlsConfigFields() []ConfigField {
+	return []ConfigField{
+		{Name: tlsKeyClientCert, Comment: "PEM client certificate for mTLS"},
+		{Name: tlsKeyClientKey, Comment: "PEM client key for mTLS"},
+		{Name: tlsKeyCACert, Comment: "PEM CA bundle trusted for the server certificate"},
+		{Name: tlsKeySPKIPin, Comment: "base64 sha256 SPKI pin the server chain must match"},
+		{Name: tlsKeyInsecureSkipTLS, Comment: `"true" disables TLS certificate verification`},
+	}
+}
+
+// ConfigFields returns the Secret keys the named provider accepts, required
+// keys first. The entries reference the same key constants the handler
+// constructors read, so scaffolded manifests cannot drift from validation.
+func ConfigFields(provider string) ([]ConfigField, error) {
+	switch provider {
+	case VaultProvider:
+		fields := []ConfigField{
+			{Name: vaultKeyAddress, Required: true, Comment: "Vault server URL"},
+			{Name: vaultKeyToken, Comment: "Vault token; alternative to tokenFile"},
+			{Name: vaultKeyTokenFile, Comment: "path inside the operator pod the token is read from"},
+			{Name: vaultKeyAuthMethod, Comment: "login method instead of a static token: kubernetes, approle or jwt"},
+			{Name: vaultKeyAuthRole, Comment: "role used by the login method"},
+			{Name: vaultKeyAuthMount, Comment: "auth mount path; defaults to the method name"},
+			{Name: vaultKeyJWTPath, Comment: "JWT file presented on login; defaults to the projected service account token"},
+			{Name: vaultKeyRoleID, Comment: "role id for approle login"},
+			{Name: vaultKeySecretID, Comment: "secret id for approle login"},
+			{Name: vaultKeyJWT, Comment: "literal JWT for jwt login; overrides jwtPath"},
+			{Name: vaultKeyNamespace, Comment: "Vault Enterprise namespace the paths live in"},
+			{Name: vaultKeyKVVersion, Comment: "KV engine version of the paths: 1 or 2; detected when empty"},
+			{Name: vaultKeyMaxRetries, Comment: "retries of failed calls; defaults to the Vault client default"},
+			{Name: vaultKeyTimeout, Comment: "per-call timeout, such as 30s"},
+			{Name: vaultKeyProxy, Comment: "HTTP proxy URL the Vault server is reached through"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case GitProvider:
+		return []ConfigField{
+			{Name: gitKeyURL, Required: true, Comment: "repository clone URL"},
+			{Name: gitKeyFile, Comment: "default configuration file; placeholders may name their own with <file>#<key>"},
+			{Name: gitKeyFileType, Comment: "format of the file: yaml (default), json, toml, ini, env or properties"},
+			{Name: gitKeyBranch, Comment: "branch to check out; defaults to main"},
+			{Name: gitKeyTag, Comment: "tag to check out instead of a branch"},
+			{Name: gitKeyCommit, Comment: "commit sha to check out instead of a branch"},
+			{Name: gitKeyRefresh, Comment: "background refresh interval, such as 2m; reads inside it skip the fetch"},
+			{Name: gitKeyUsername, Comment: "username for HTTP basic auth"},
+			{Name: gitKeyPassword, Comment: "password or token for HTTP basic auth"},
+			{Name: gitKeySSHPrivateKey, Comment: "PEM private key for SSH auth"},
+			{Name: gitKeySSHPassphrase, Comment: "passphrase of the SSH private key"},
+			{Name: gitKeyKnownHosts, Comment: "known_hosts entries of the SSH server; required with sshPrivateKey"},
+			{Name: gitKeyInsecureSkipHostKey, Comment: "\"true\" disables SSH host key verification"},
+			{Name: gitKeyGitHubAppID, Comment: "GitHub App id minting installation tokens instead of a PAT"},
+			{Name: gitKeyGitHubInstallationID, Comment: "installation id of the GitHub App"},
+			{Name: gitKeyGitHubPrivateKey, Comment: "PEM private key of the GitHub App"},
+			{Name: gitKeyProxyURL, Comment: "HTTP proxy URL the git host is reached through"},
+			{Name: gitKeyProxyUsername, Comment: "username for proxy authentication"},
+			{Name: gitKeyProxyPassword, Comment: "password for proxy authentication"},
+			{Name: tlsKeyCACert, Comment: "additional PEM CA bundle trusted for the repository host"},
+			{Name: tlsKeyInsecureSkipTLS, Comment: "\"true\" disables TLS certificate verification"},
+			{Name: gitKeySOPSAgeKey, Comment: "age identity used to decrypt SOPS-encrypted files"},
+			{Name: gitKeyLFS, Comment: "\"true\" resolves Git LFS pointer files to the real object through the LFS API"},
+			{Name: gitKeyRecurseSubmodules, Comment: "\"true\" clones submodules as well, so files inside them resolve"},
+		}, nil
+	case SSMProvider:
+		fields := []ConfigField{
+			{Name: awsKeyRegion, Required: true, Comment: "AWS region of the parameters"},
+		}
+
+		return append(fields, awsConfigFields()...), nil
+	case GCPSMProvider:
+		return []ConfigField{
+			{Name: gcpsmKeyCredentialsJSON, Comment: "service-account JSON key; omit to use the SDK default chain (Workload Identity)"},
+		}, nil
+	case K8sSecretProvider:
+		return []ConfigField{
+			{Name: k8ssecretKeyAllowedNamespaces, Required: true, Comment: `comma-separated namespaces readable through this provider; "*" allows all`},
+		}, nil
+	case ConfigMapProvider:
+		return []ConfigField{
+			{Name: configmapKeyName, Required: true, Comment: "name of the ConfigMap values are read from"},
+			{Name: configmapKeyNamespace, Comment: "namespace of the ConfigMap; defaults to the provider Secret namespace"},
+		}, nil
+	case HTTPProvider:
+		fields := []ConfigField{
+			{Name: httpKeyURL, Required: true, Comment: "URL of the endpoint values are fetched from"},
+			{Name: httpKeyBearerToken, Comment: "bearer token sent in the Authorization header"},
+			{Name: httpKeyUsername, Comment: "username for HTTP basic auth"},
+			{Name: httpKeyPassword, Comment: "password for HTTP basic auth"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case ConsulProvider:
+		fields := []ConfigField{
+			{Name: consulKeyAddress, Required: true, Comment: "Consul HTTP address"},
+			{Name: consulKeyToken, Comment: "ACL token"},
+			{Name: consulKeyDatacenter, Comment: "datacenter queried; defaults to that of the agent"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case EtcdProvider:
+		fields := []ConfigField{
+			{Name: etcdKeyEndpoints, Required: true, Comment: "comma-separated etcd endpoints"},
+			{Name: etcdKeyUsername, Comment: "username for etcd authentication"},
+			{Name: etcdKeyPassword, Comment: "password for etcd authentication"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case S3Provider:
+		fields := []ConfigField{
+			{Name: s3KeyBucket, Required: true, Comment: "bucket holding the configuration file"},
+			{Name: s3KeyObjectKey, Required: true, Comment: "object key of the configuration file"},
+			{Name: awsKeyRegion, Required: true, Comment: "AWS region of the bucket"},
+			{Name: s3KeyEndpoint, Comment: "custom endpoint for S3-compatible stores such as MinIO"},
+			{Name: s3KeyFileType, Comment: "file type of the object: yaml (default, covers JSON) or ini"},
+		}
+
+		return append(fields, awsConfigFields()...), nil
+	case ConjurProvider:
+		fields := []ConfigField{
+			{Name: conjurKeyApplianceURL, Required: true, Comment: "Conjur appliance URL"},
+			{Name: conjurKeyAccount, Required: true, Comment: "Conjur organization account"},
+			{Name: conjurKeyLogin, Comment: "host or user login for API-key authentication"},
+			{Name: conjurKeyAPIKey, Comment: "API key paired with login"},
+			{Name: conjurKeyJWTServiceID, Comment: "authn-jwt service id for JWT authentication"},
+			{Name: conjurKeyJWT, Comment: "JWT paired with jwtServiceID"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case OnePasswordProvider:
+		fields := []ConfigField{
+			{Name: onepasswordKeyHost, Required: true, Comment: "1Password Connect server URL"},
+			{Name: onepasswordKeyToken, Required: true, Comment: "Connect access token"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	case DopplerProvider:
+		return []ConfigField{
+			{Name: dopplerKeyToken, Required: true, Comment: "Doppler service token"},
+			{Name: dopplerKeyProject, Comment: "project; only needed with tokens not scoped to one config"},
+			{Name: dopplerKeyConfig, Comment: "config; only needed with tokens not scoped to one config"},
+			{Name: dopplerKeyAPIURL, Comment: "API URL; defaults to the hosted Doppler API"},
+		}, nil
+	case AkeylessProvider:
+		return []ConfigField{
+			{Name: akeylessKeyAccessID, Required: true, Comment: "Akeyless access id"},
+			{Name: akeylessKeyAccessKey, Comment: "access key; alternative to jwt"},
+			{Name: akeylessKeyJWT, Comment: "JWT for machine-identity authentication"},
+			{Name: akeylessKeyAPIURL, Comment: "gateway API URL; defaults to the hosted Akeyless API"},
+		}, nil
+	case InfisicalProvider:
+		return []ConfigField{
+			{Name: infisicalKeyToken, Required: true, Comment: "machine-identity access token"},
+			{Name: infisicalKeyWorkspaceID, Required: true, Comment: "workspace (project) id secrets are read from"},
+			{Name: infisicalKeyAPIURL, Comment: "API URL; defaults to the hosted Infisical API"},
+		}, nil
+	case GitLabProvider:
+		return []ConfigField{
+			{Name: gitlabKeyToken, Required: true, Comment: "personal or project access token with read_api scope"},
+			{Name: gitlabKeyProjectID, Comment: "project id or path; exactly one of projectID or groupID"},
+			{Name: gitlabKeyGroupID, Comment: "group id or path; exactly one of projectID or groupID"},
+			{Name: gitlabKeyURL, Comment: "instance URL; defaults to gitlab.com"},
+		}, nil
+	case PluginProvider:
+		fields := []ConfigField{
+			{Name: pluginKeyEndpoint, Required: true, Comment: "plugin endpoint: unix:///path.sock, http:// or https://"},
+			{Name: pluginKeyToken, Comment: "bearer token sent to the plugin"},
+		}
+
+		return append(fields, tlsConfigFields()...), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
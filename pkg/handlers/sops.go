@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// sopsValueRegex matches one SOPS-encrypted scalar of the form
+// ENC[AES256_GCM,data:...,iv:...,tag:...,type:...].
+var sopsValueRegex = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// isSOPSEncrypted reports whether a parsed configuration file carries SOPS
+// metadata.
+func isSOPSEncrypted(values map[string]interface{}) bool {
+	_, ok := values["sops"]
+
+	return ok
+}
+
+// decryptSOPS decrypts a SOPS-encrypted configuration tree in place using an
+// age identity, and strips the sops metadata section. The data key is
+// unwrapped from the age recipient stanzas of the metadata; every scalar is
+// then authenticated and decrypted individually by AES-GCM with its tree
+// path as additional data, so values cannot be swapped between keys.
+func decryptSOPS(values map[string]interface{}, ageKey string) error {
+	identity, err := age.ParseX25519Identity(strings.TrimSpace(ageKey))
+	if err != nil {
+		return fmt.Errorf("invalid sopsAgeKey: %w", err)
+	}
+
+	metadata, ok := toStringMap(values["sops"])
+	if !ok {
+		return fmt.Errorf("malformed sops metadata")
+	}
+
+	dataKey, err := sopsDataKey(metadata, identity)
+	if err != nil {
+		return err
+	}
+
+	delete(values, "sops")
+
+	return decryptSOPSBranch(values, nil, dataKey)
+}
+
+// sopsDataKey unwraps the file data key from the age recipient stanzas of
+// the SOPS metadata.
+func sopsDataKey(metadata map[string]interface{}, identity *age.X25519Identity) ([]byte, error) {
+	recipients, _ := metadata["age"].([]interface{})
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("sops file has no age recipients; only age keys are supported")
+	}
+
+	var lastErr error
+
+	for _, recipient := range recipients {
+		entry, ok := toStringMap(recipient)
+		if !ok {
+			continue
+		}
+
+		enc, _ := entry["enc"].(string)
+		if len(enc) == 0 {
+			continue
+		}
+
+		reader, err := age.Decrypt(armor.NewReader(strings.NewReader(enc)), identity)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		dataKey, err := io.ReadAll(reader)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return dataKey, nil
+	}
+
+	return nil, fmt.Errorf("unable to unwrap the sops data key with the configured age key: %w", lastErr)
+}
+
+// decryptSOPSBranch walks one branch of the tree, decrypting every scalar.
+func decryptSOPSBranch(branch map[string]interface{}, path []string, dataKey []byte) error {
+	for key, value := range branch {
+		decrypted, err := decryptSOPSValue(value, append(path, key), dataKey)
+		if err != nil {
+			return err
+		}
+
+		branch[key] = decrypted
+	}
+
+	return nil
+}
+
+// decryptSOPSValue decrypts one value at the given tree path. Maps recurse,
+// list items share the path of their list, and scalars are decrypted with
+// the joined path as additional data.
+func decryptSOPSValue(value interface{}, path []string, dataKey []byte) (interface{}, error) {
+	if m, ok := toStringMap(value); ok {
+		if err := decryptSOPSBranch(m, path, dataKey); err != nil {
+			return nil, err
+		}
+
+		return m, nil
+	}
+
+	if list, ok := value.([]interface{}); ok {
+		for i, item := range list {
+			decrypted, err := decryptSOPSValue(item, path, dataKey)
+			if err != nil {
+				return nil, err
+			}
+
+			list[i] = decrypted
+		}
+
+		return list, nil
+	}
+
+	encrypted, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	match := sopsValueRegex.FindStringSubmatch(encrypted)
+	if match == nil {
+		return value, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed sops value at %s: %w", strings.Join(path, "."), err)
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed sops value at %s: %w", strings.Join(path, "."), err)
+	}
+
+	tag, err := base64.StdEncoding.DecodeString(match[3])
+	if err != nil {
+		return nil, fmt.Errorf("malformed sops value at %s: %w", strings.Join(path, "."), err)
+	}
+
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sops data key: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	additionalData := []byte(strings.Join(path, ":") + ":")
+
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt sops value at %s: %w", strings.Join(path, "."), err)
+	}
+
+	// Scalars come back as strings regardless of the declared type; lookups
+	// format values with %v anyway.
+	return string(plaintext), nil
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+func init() {
+	Register(SSMProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewSSMHandler(secret)
+	})
+}
+
+// SSMHandler resolves placeholders of the form {{ ssm:<secret>:<parameter> }}
+// against AWS Systems Manager Parameter Store. The parameter is the full
+// (path-based) parameter name, e.g. /prod/app/db_password. SecureString
+// parameters are decrypted transparently.
+type SSMHandler struct {
+	client *ssm.SSM
+}
+
+// NewSSMHandler builds an SSMHandler from a provider Secret, using the
+// shared AWS session keys.
+func NewSSMHandler(secret *corev1.Secret) (*SSMHandler, error) {
+	sess, err := newAWSSession(secret, aws.NewConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSMHandler{client: ssm.New(sess)}, nil
+}
+
+// Check verifies Parameter Store is reachable with the configured
+// credentials.
+func (h *SSMHandler) Check(ctx context.Context) error {
+	input := &ssm.DescribeParametersInput{MaxResults: aws.Int64(1)}
+
+	if _, err := h.client.DescribeParametersWithContext(ctx, input); err != nil {
+		return fmt.Errorf("parameter store unreachable: %w", err)
+	}
+
+	return nil
+}
+
+// GetData reads the parameter named data, decrypting SecureString values.
+func (h *SSMHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	input := &ssm.GetParameterInput{
+		Name:           aws.String(data),
+		WithDecryption: aws.Bool(true),
+	}
+
+	output, err := h.client.GetParameterWithContext(ctx, input)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) {
+			switch awsErr.Code() {
+			case ssm.ErrCodeParameterNotFound:
+				return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("ssm parameter %s not found", data))
+			case "AccessDeniedException", "UnrecognizedClientException", "InvalidSignatureException", "ExpiredTokenException":
+				return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read ssm parameter %s: %w", data, err))
+			}
+		}
+
+		return none, fmt.Errorf("unable to read ssm parameter %s: %w", data, err)
+	}
+
+	if output.Parameter == nil || output.Parameter.Value == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("ssm parameter %s has no value", data))
+	}
+
+	return redact.Sensitive(aws.StringValue(output.Parameter.Value)), nil
+}
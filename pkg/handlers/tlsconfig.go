@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	nethttp "net/http"
+
+	gitclient "github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fipsMode restricts provider connections to FIPS-approved TLS settings.
+// Set from the --fips-mode flag for regulated deployments.
+var fipsMode bool
+
+// SetFIPSMode configures whether provider connections are restricted to
+// TLS 1.2+ with FIPS-approved cipher suites. Enabling it also installs a
+// restricted base transport for go-git, whose clones and fetches otherwise
+// bypass buildTLSConfig; per-Secret caCert, insecureSkipTLS and proxy
+// options are layered on a clone of this transport by go-git itself.
+func SetFIPSMode(enabled bool) {
+	fipsMode = enabled
+
+	if enabled {
+		gitclient.InstallProtocol("https", githttp.NewClient(&nethttp.Client{
+			Transport: &nethttp.Transport{
+				TLSClientConfig: fipsTLSConfig(),
+				Proxy:           nethttp.ProxyFromEnvironment,
+			},
+		}))
+	}
+}
+
+// fipsTLSConfig is the baseline TLS configuration enforced under fips mode.
+func fipsTLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, CipherSuites: fipsCipherSuites}
+}
+
+// fipsCipherSuites are the FIPS-approved TLS 1.2 cipher suites. TLS 1.3
+// suites are not configurable and are all approved.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+}
+
+// Optional TLS keys shared by provider Secrets.
+const (
+	tlsKeyClientCert      = "clientCert"
+	tlsKeyClientKey       = "clientKey"
+	tlsKeyCACert          = "caCert"
+	tlsKeySPKIPin         = "spkiPin"
+	tlsKeyInsecureSkipTLS = "insecureSkipTLS"
+)
+
+// buildTLSConfig builds a tls.Config from the optional TLS keys of a
+// provider Secret:
+//
+//   - clientCert, clientKey: PEM client certificate and key for mTLS.
+//   - caCert: PEM CA bundle trusted for the server certificate.
+//   - spkiPin: base64 sha256 of a Subject Public Key Info; the connection is
+//     rejected unless some certificate in the presented chain matches.
+//   - insecureSkipTLS: "true" disables certificate verification entirely;
+//     rejected when the operator forbids insecure TLS.
+//
+// It returns nil when the Secret carries none of these keys, so callers keep
+// the default transport.
+func buildTLSConfig(secret *corev1.Secret) (*tls.Config, error) {
+	clientCert := secret.Data[tlsKeyClientCert]
+	clientKey := secret.Data[tlsKeyClientKey]
+	caCert := secret.Data[tlsKeyCACert]
+	spkiPin := string(secret.Data[tlsKeySPKIPin])
+	insecureSkipTLS := string(secret.Data[tlsKeyInsecureSkipTLS]) == "true"
+
+	if len(clientCert) == 0 && len(clientKey) == 0 && len(caCert) == 0 && len(spkiPin) == 0 && !insecureSkipTLS && !fipsMode {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if insecureSkipTLS {
+		if err := checkInsecureTLSAllowed(secret.Name); err != nil {
+			return nil, err
+		}
+
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if fipsMode {
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.CipherSuites = fipsCipherSuites
+	}
+
+	if len(clientCert) > 0 || len(clientKey) > 0 {
+		pair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("provider secret %s has an invalid client certificate: %w", secret.Name, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	if len(caCert) > 0 {
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("provider secret %s has an invalid caCert bundle", secret.Name)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(spkiPin) > 0 {
+		tlsConfig.VerifyPeerCertificate = verifySPKIPin(spkiPin)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifySPKIPin accepts a connection when any certificate in the presented
+// chain has a Subject Public Key Info whose base64 sha256 matches pin.
+func verifySPKIPin(pin string) func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				continue
+			}
+
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+			if base64.StdEncoding.EncodeToString(sum[:]) == pin {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("no certificate in the chain matches the pinned SPKI")
+	}
+}
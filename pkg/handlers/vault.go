@@ -0,0 +1,827 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"golang.org/x/sync/singleflight"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/redact"
+)
+
+// Secret keys of the vault provider configuration.
+const (
+	vaultKeyAddress    = "address"
+	vaultKeyToken      = "token"
+	vaultKeyTokenFile  = "tokenFile"
+	vaultKeyAuthMethod = "authMethod"
+	vaultKeyAuthMount  = "authMount"
+	vaultKeyAuthRole   = "authRole"
+	vaultKeyJWTPath    = "jwtPath"
+	vaultKeyRoleID     = "roleID"
+	vaultKeySecretID   = "secretID"
+	vaultKeyJWT        = "jwt"
+	vaultKeyNamespace  = "namespace"
+	vaultKeyKVVersion  = "kvVersion"
+	vaultKeyMaxRetries = "maxRetries"
+	vaultKeyTimeout    = "timeout"
+	vaultKeyProxy      = "proxy"
+)
+
+// Vault auth methods accepted under the authMethod key. The default, an
+// empty value, expects a static token or tokenFile.
+const (
+	vaultAuthKubernetes = "kubernetes"
+	vaultAuthAppRole    = "approle"
+	vaultAuthJWT        = "jwt"
+)
+
+// vaultDefaultJWTPath is where the kubelet projects the ServiceAccount
+// token of the operator pod.
+const vaultDefaultJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// vaultLoginLeeway is how long before token expiry a fresh login is
+// performed.
+const vaultLoginLeeway = time.Minute
+
+// vaultPKIIssueSegment marks a path as a PKI issue endpoint, which is
+// written to rather than read.
+const vaultPKIIssueSegment = "/issue/"
+
+// vaultReadCoalesceTTL is how long a read response is shared between
+// placeholders. It spans the concurrent manifest renders of one reconcile,
+// so several keys of the same path cost one HTTP read, while staying short
+// enough that the next reconcile sees fresh data.
+const vaultReadCoalesceTTL = 5 * time.Second
+
+func init() {
+	Register(VaultProvider, func(_ client.Client, secret *corev1.Secret) (Handler, error) {
+		return NewVaultHandler(secret)
+	})
+}
+
+// VaultHandler resolves placeholders of the form
+// {{ vault:<secret>:<path>#<key> }} against a HashiCorp Vault server. The
+// provider Secret supplies the connection data under the key "address" plus
+// either "token" or "tokenFile".
+type VaultHandler struct {
+	client *vaultapi.Client
+
+	// tokenFile, when set, is a path inside the operator pod (a Vault Agent
+	// sink or projected volume) the token is read from. The file is re-read
+	// before a call whenever its modification time changes, so renewed
+	// tokens are picked up without copying them into a Kubernetes Secret.
+	tokenFile    string
+	tokenMu      sync.Mutex
+	tokenModTime time.Time
+
+	// authMethod, when set, makes the handler log in to Vault instead of
+	// using a static token. Tokens obtained by login are reused across
+	// reconciles until shortly before they expire.
+	authMethod  string
+	authMount   string
+	authRole    string
+	jwtPath     string
+	jwt         string
+	roleID      string
+	secretID    string
+	tokenExpiry time.Time
+
+	// watcher renews a renewable login token in the background for as long
+	// as Vault allows; renewErr holds the error of the last failed renewal
+	// until a later renewal or login succeeds.
+	watcher  *vaultapi.LifetimeWatcher
+	renewErr error
+
+	// kvVersion pins the KV engine version the paths of this provider point
+	// at: "1" reads the secret map directly, "2" unwraps the nested data
+	// field, and an empty value falls back to detecting the v2 nesting in
+	// the response.
+	kvVersion string
+
+	// leases caches responses of dynamic secret engines by path for the
+	// duration of their lease, so every placeholder keyed on the same path
+	// sees one set of credentials instead of minting new ones per read.
+	// Renewable leases are renewed shortly before expiry; an expired lease
+	// is dropped, and the next read rotates the credentials.
+	leaseMu sync.Mutex
+	leases  map[string]*vaultLease
+
+	// reads coalesces static secret reads: concurrent reads of one path
+	// share a single call through flight, and the response is served to
+	// further placeholders for vaultReadCoalesceTTL.
+	readMu sync.Mutex
+	reads  map[string]vaultReadEntry
+	flight singleflight.Group
+}
+
+// vaultReadEntry is one coalesced read response.
+type vaultReadEntry struct {
+	values  map[string]interface{}
+	fetched time.Time
+}
+
+// vaultLease is one cached dynamic secret response.
+type vaultLease struct {
+	values    map[string]interface{}
+	leaseID   string
+	renewable bool
+	expires   time.Time
+}
+
+// NewVaultHandler builds a VaultHandler from a provider Secret.
+func NewVaultHandler(secret *corev1.Secret) (*VaultHandler, error) {
+	address := string(secret.Data[vaultKeyAddress])
+	token := string(secret.Data[vaultKeyToken])
+	tokenFile := string(secret.Data[vaultKeyTokenFile])
+	authMethod := string(secret.Data[vaultKeyAuthMethod])
+
+	if len(address) == 0 {
+		return nil, fmt.Errorf("vault provider secret %s is missing the address key", secret.Name)
+	}
+
+	switch authMethod {
+	case "":
+		if len(token) == 0 && len(tokenFile) == 0 {
+			return nil, fmt.Errorf("vault provider secret %s is missing the token or tokenFile key", secret.Name)
+		}
+	case vaultAuthKubernetes:
+		if len(secret.Data[vaultKeyAuthRole]) == 0 {
+			return nil, fmt.Errorf("vault provider secret %s is missing the authRole key required by authMethod %s", secret.Name, authMethod)
+		}
+	case vaultAuthAppRole:
+		if len(secret.Data[vaultKeyRoleID]) == 0 || len(secret.Data[vaultKeySecretID]) == 0 {
+			return nil, fmt.Errorf("vault provider secret %s is missing the roleID or secretID key required by authMethod %s", secret.Name, authMethod)
+		}
+	case vaultAuthJWT:
+		if len(secret.Data[vaultKeyAuthRole]) == 0 {
+			return nil, fmt.Errorf("vault provider secret %s is missing the authRole key required by authMethod %s", secret.Name, authMethod)
+		}
+	default:
+		return nil, fmt.Errorf("vault provider secret %s has unsupported authMethod %q", secret.Name, authMethod)
+	}
+
+	config := vaultapi.DefaultConfig()
+	config.Address = address
+
+	// Retry and timeout tuning keeps flaky Vault endpoints from failing a
+	// reconcile on the first hiccup.
+	if maxRetries := string(secret.Data[vaultKeyMaxRetries]); len(maxRetries) > 0 {
+		retries, err := strconv.Atoi(maxRetries)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("vault provider secret %s has invalid maxRetries %q", secret.Name, maxRetries)
+		}
+
+		config.MaxRetries = retries
+	}
+
+	if timeout := string(secret.Data[vaultKeyTimeout]); len(timeout) > 0 {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("vault provider secret %s has invalid timeout %q", secret.Name, timeout)
+		}
+
+		config.Timeout = duration
+	}
+
+	// Optional mTLS client certificate, CA bundle and SPKI pin from the same
+	// Secret.
+	tlsConfig, err := buildTLSConfig(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := string(secret.Data[vaultKeyProxy])
+
+	if tlsConfig != nil || len(proxy) > 0 {
+		transport, ok := config.HttpClient.Transport.(*nethttp.Transport)
+		if !ok {
+			return nil, fmt.Errorf("unable to configure the vault transport")
+		}
+
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig
+		}
+
+		// Corporate proxy in front of the Vault server.
+		if len(proxy) > 0 {
+			proxyURL, err := url.Parse(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("vault provider secret %s has invalid proxy %q: %w", secret.Name, proxy, err)
+			}
+
+			transport.Proxy = nethttp.ProxyURL(proxyURL)
+		}
+	}
+
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vault client: %w", err)
+	}
+
+	client.SetToken(token)
+
+	// Vault Enterprise namespace the paths of this provider live in; sent as
+	// the X-Vault-Namespace header on every call.
+	if namespace := string(secret.Data[vaultKeyNamespace]); len(namespace) > 0 {
+		client.SetNamespace(namespace)
+	}
+
+	kvVersion := string(secret.Data[vaultKeyKVVersion])
+
+	switch kvVersion {
+	case "", "1", "2":
+	default:
+		return nil, fmt.Errorf("vault provider secret %s has unsupported kvVersion %q", secret.Name, kvVersion)
+	}
+
+	h := &VaultHandler{
+		client:     client,
+		tokenFile:  tokenFile,
+		kvVersion:  kvVersion,
+		leases:     map[string]*vaultLease{},
+		reads:      map[string]vaultReadEntry{},
+		authMethod: authMethod,
+		authMount:  string(secret.Data[vaultKeyAuthMount]),
+		authRole:   string(secret.Data[vaultKeyAuthRole]),
+		jwtPath:    string(secret.Data[vaultKeyJWTPath]),
+		jwt:        string(secret.Data[vaultKeyJWT]),
+		roleID:     string(secret.Data[vaultKeyRoleID]),
+		secretID:   string(secret.Data[vaultKeySecretID]),
+	}
+
+	// The auth mount defaults to the conventional mount path of the method.
+	if len(h.authMethod) > 0 && len(h.authMount) == 0 {
+		h.authMount = h.authMethod
+	}
+
+	// Both JWT-based methods fall back to the projected service account
+	// token; for authMethod jwt that is typically an audience-scoped
+	// projection rather than the default kubelet token.
+	needsJWTFile := h.authMethod == vaultAuthKubernetes || (h.authMethod == vaultAuthJWT && len(h.jwt) == 0)
+
+	if needsJWTFile && len(h.jwtPath) == 0 {
+		h.jwtPath = vaultDefaultJWTPath
+	}
+
+	if len(tokenFile) > 0 && len(authMethod) == 0 {
+		if err := h.refreshToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	return h, nil
+}
+
+// loginPayload builds the body of the login call of the configured auth
+// method.
+func (h *VaultHandler) loginPayload() (map[string]interface{}, error) {
+	switch h.authMethod {
+	case vaultAuthKubernetes, vaultAuthJWT:
+		jwt := h.jwt
+
+		if len(jwt) == 0 {
+			raw, err := os.ReadFile(h.jwtPath)
+			if err != nil {
+				return nil, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read service account token %s: %w", h.jwtPath, err))
+			}
+
+			jwt = string(raw)
+		}
+
+		return map[string]interface{}{
+			"jwt":  strings.TrimSpace(jwt),
+			"role": h.authRole,
+		}, nil
+	case vaultAuthAppRole:
+		return map[string]interface{}{
+			"role_id":   h.roleID,
+			"secret_id": h.secretID,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported vault auth method %q", h.authMethod)
+	}
+}
+
+// login performs the login call of the configured auth method, reusing the
+// obtained token until shortly before it expires so reconciles do not log
+// in repeatedly.
+func (h *VaultHandler) login(ctx context.Context) error {
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	if time.Now().Before(h.tokenExpiry.Add(-vaultLoginLeeway)) {
+		return nil
+	}
+
+	payload, err := h.loginPayload()
+	if err != nil {
+		return err
+	}
+
+	secret, err := h.client.Logical().WriteWithContext(ctx, fmt.Sprintf("auth/%s/login", h.authMount), payload)
+	if err != nil {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("vault %s login failed: %w", h.authMethod, err))
+	}
+
+	if secret == nil || secret.Auth == nil {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("vault %s login returned no token", h.authMethod))
+	}
+
+	h.client.SetToken(secret.Auth.ClientToken)
+	h.tokenExpiry = time.Now().Add(time.Duration(secret.Auth.LeaseDuration) * time.Second)
+	h.renewErr = nil
+
+	if secret.Auth.Renewable {
+		h.watchToken(secret)
+	}
+
+	return nil
+}
+
+// watchToken starts a background renewer keeping the login token alive, so
+// placeholders are served without re-logging-in on every reconcile. Called
+// with tokenMu held.
+func (h *VaultHandler) watchToken(secret *vaultapi.Secret) {
+	watcher, err := h.client.NewLifetimeWatcher(&vaultapi.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		// Renewal is an optimisation; without a watcher the next call past
+		// the expiry leeway simply logs in again.
+		return
+	}
+
+	if h.watcher != nil {
+		h.watcher.Stop()
+	}
+
+	h.watcher = watcher
+
+	go watcher.Start()
+
+	go func() {
+		for {
+			select {
+			case err := <-watcher.DoneCh():
+				h.tokenMu.Lock()
+				if err != nil {
+					h.renewErr = err
+				}
+
+				// The token is no longer renewed; force a fresh login on
+				// the next call.
+				h.tokenExpiry = time.Time{}
+				h.tokenMu.Unlock()
+
+				return
+			case renewal := <-watcher.RenewCh():
+				h.tokenMu.Lock()
+				h.renewErr = nil
+				h.tokenExpiry = time.Now().Add(time.Duration(renewal.Secret.Auth.LeaseDuration) * time.Second)
+				h.tokenMu.Unlock()
+			}
+		}
+	}()
+}
+
+// RenewalError returns the error of the last failed background token
+// renewal, or nil while renewal is healthy.
+func (h *VaultHandler) RenewalError() error {
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	return h.renewErr
+}
+
+// Close stops the background token renewer. Called when the handler is
+// evicted from the pool because its provider Secret changed.
+func (h *VaultHandler) Close() {
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	if h.watcher != nil {
+		h.watcher.Stop()
+		h.watcher = nil
+	}
+}
+
+// ensureAuth makes sure the client carries a usable token before a call,
+// whatever the configured auth method.
+func (h *VaultHandler) ensureAuth(ctx context.Context) error {
+	if len(h.authMethod) > 0 {
+		return h.login(ctx)
+	}
+
+	return h.refreshToken()
+}
+
+// refreshToken loads the token from tokenFile when the file changed since it
+// was last read.
+func (h *VaultHandler) refreshToken() error {
+	if len(h.tokenFile) == 0 {
+		return nil
+	}
+
+	h.tokenMu.Lock()
+	defer h.tokenMu.Unlock()
+
+	info, err := os.Stat(h.tokenFile)
+	if err != nil {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to stat vault token file %s: %w", h.tokenFile, err))
+	}
+
+	if info.ModTime().Equal(h.tokenModTime) {
+		return nil
+	}
+
+	token, err := os.ReadFile(h.tokenFile)
+	if err != nil {
+		return errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read vault token file %s: %w", h.tokenFile, err))
+	}
+
+	h.client.SetToken(strings.TrimSpace(string(token)))
+	h.tokenModTime = info.ModTime()
+
+	return nil
+}
+
+// Check verifies the Vault server is reachable and unsealed.
+func (h *VaultHandler) Check(ctx context.Context) error {
+	if err := h.ensureAuth(ctx); err != nil {
+		return err
+	}
+
+	health, err := h.client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("vault server unreachable: %w", err)
+	}
+
+	if health.Sealed {
+		return fmt.Errorf("vault server is sealed")
+	}
+
+	return nil
+}
+
+// leasedValues serves a dynamic secret from the lease cache, renewing a
+// renewable lease when it is about to expire. The second return is false
+// when the path has no live lease and must be read again.
+func (h *VaultHandler) leasedValues(ctx context.Context, path string) (map[string]interface{}, bool) {
+	h.leaseMu.Lock()
+
+	lease, ok := h.leases[path]
+	if !ok {
+		h.leaseMu.Unlock()
+		return nil, false
+	}
+
+	leaseID := lease.leaseID
+
+	if !lease.renewable || time.Until(lease.expires) >= vaultLoginLeeway {
+		defer h.leaseMu.Unlock()
+
+		if time.Now().After(lease.expires) {
+			delete(h.leases, path)
+			return nil, false
+		}
+
+		return lease.values, true
+	}
+
+	h.leaseMu.Unlock()
+
+	// The renewal is a network round-trip; run it unlocked so one slow or
+	// hung renewal does not block every other lease read on this handler.
+	var renewedUntil time.Time
+
+	if renewed, err := h.client.Sys().RenewWithContext(ctx, leaseID, 0); err == nil && renewed != nil {
+		renewedUntil = time.Now().Add(time.Duration(renewed.LeaseDuration) * time.Second)
+	}
+
+	h.leaseMu.Lock()
+	defer h.leaseMu.Unlock()
+
+	// The entry may have been replaced by a fresh read while unlocked; only
+	// apply the result to the lease the renewal was for.
+	lease, ok = h.leases[path]
+	if !ok {
+		return nil, false
+	}
+
+	if lease.leaseID == leaseID && !renewedUntil.IsZero() {
+		lease.expires = renewedUntil
+	}
+
+	if time.Now().After(lease.expires) {
+		delete(h.leases, path)
+		return nil, false
+	}
+
+	return lease.values, true
+}
+
+// storeLease caches a leased response so follow-up reads of the same path
+// reuse the credentials for the lifetime of the lease.
+func (h *VaultHandler) storeLease(path string, secret *vaultapi.Secret, values map[string]interface{}) {
+	h.leaseMu.Lock()
+	defer h.leaseMu.Unlock()
+
+	h.leases[path] = &vaultLease{
+		values:    values,
+		leaseID:   secret.LeaseID,
+		renewable: secret.Renewable,
+		expires:   time.Now().Add(time.Duration(secret.LeaseDuration) * time.Second),
+	}
+}
+
+// NextRotation reports the earliest expiry among the live leases of this
+// handler, so reconciles can be scheduled before credentials rotate.
+func (h *VaultHandler) NextRotation() (time.Time, bool) {
+	h.leaseMu.Lock()
+	defer h.leaseMu.Unlock()
+
+	var earliest time.Time
+
+	for _, lease := range h.leases {
+		if earliest.IsZero() || lease.expires.Before(earliest) {
+			earliest = lease.expires
+		}
+	}
+
+	return earliest, !earliest.IsZero()
+}
+
+// GetData reads the Vault secret at <path> and returns the field <key> from
+// data of the form <path>#<key>. A trailing #v=<n> segment pins a KV v2
+// secret version, so rendered manifests stay reproducible across rotations.
+func (h *VaultHandler) GetData(ctx context.Context, data string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	if err := h.ensureAuth(ctx); err != nil {
+		return none, err
+	}
+
+	// A path ending in a slash enumerates the child keys under it instead
+	// of reading a secret.
+	if strings.HasSuffix(data, "/") && !strings.Contains(data, "#") {
+		return h.listPath(ctx, data)
+	}
+
+	parts := strings.Split(data, "#")
+
+	if len(parts) < 2 || len(parts) > 3 {
+		return none, fmt.Errorf("vault placeholder data %q must have the form <path>#<key> with an optional #v=<n> suffix", data)
+	}
+
+	path := parts[0]
+	key := parts[1]
+	version := ""
+
+	if len(parts) == 3 {
+		var found bool
+
+		version, found = strings.CutPrefix(parts[2], "v=")
+		if !found {
+			return none, fmt.Errorf("vault placeholder data %q has an invalid version segment %q; expected v=<n>", data, parts[2])
+		}
+	}
+
+	// Dynamic engines (database credentials, issued certificates) are served
+	// from the lease cache while their lease lives.
+	if values, ok := h.leasedValues(ctx, path); ok {
+		value, found := values[key]
+		if !found {
+			return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found at vault path %s", key, path))
+		}
+
+		return redact.Sensitive(vaultFieldString(value)), nil
+	}
+
+	// PKI issue endpoints mint a certificate instead of reading a stored
+	// secret.
+	if strings.Contains(path, vaultPKIIssueSegment) {
+		return h.issueCertificate(ctx, path, key)
+	}
+
+	values, err := h.readPath(ctx, path, version)
+	if err != nil {
+		return none, err
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found at vault path %s", key, path))
+	}
+
+	return redact.Sensitive(vaultFieldString(value)), nil
+}
+
+// listPath enumerates the child keys under a path, rendered as a YAML flow
+// sequence ("[a, b, c]") so manifests can project the list of secrets below
+// a prefix, such as tenant names under secret/metadata/tenants/.
+func (h *VaultHandler) listPath(ctx context.Context, path string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	secret, err := h.client.Logical().ListWithContext(ctx, strings.TrimSuffix(path, "/"))
+	if err != nil {
+		var responseErr *vaultapi.ResponseError
+		if errors.As(err, &responseErr) &&
+			(responseErr.StatusCode == 401 || responseErr.StatusCode == 403) {
+			return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to list vault path %s: %w", path, err))
+		}
+
+		return none, fmt.Errorf("unable to list vault path %s: %w", path, err)
+	}
+
+	if secret == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("vault path %s has no child keys", path))
+	}
+
+	children, ok := secret.Data["keys"].([]interface{})
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("vault path %s has no child keys", path))
+	}
+
+	keys := make([]string, 0, len(children))
+
+	for _, child := range children {
+		keys = append(keys, fmt.Sprintf("%v", child))
+	}
+
+	sort.Strings(keys)
+
+	return redact.Sensitive("[" + strings.Join(keys, ", ") + "]"), nil
+}
+
+// readPath fetches the secret map of a path, coalescing reads: concurrent
+// placeholders share one in-flight call, and the response is reused for
+// vaultReadCoalesceTTL so every key of one path costs one HTTP read per
+// reconcile.
+func (h *VaultHandler) readPath(ctx context.Context, path, version string) (map[string]interface{}, error) {
+	cacheKey := path
+
+	if len(version) > 0 {
+		cacheKey = path + "#v=" + version
+	}
+
+	h.readMu.Lock()
+	entry, cached := h.reads[cacheKey]
+	h.readMu.Unlock()
+
+	if cached && time.Since(entry.fetched) < vaultReadCoalesceTTL {
+		return entry.values, nil
+	}
+
+	result, err, _ := h.flight.Do(cacheKey, func() (interface{}, error) {
+		var query map[string][]string
+
+		if len(version) > 0 {
+			query = map[string][]string{"version": {version}}
+		}
+
+		secret, err := h.client.Logical().ReadWithDataWithContext(ctx, path, query)
+		if err != nil {
+			var responseErr *vaultapi.ResponseError
+			if errors.As(err, &responseErr) &&
+				(responseErr.StatusCode == 401 || responseErr.StatusCode == 403) {
+				return nil, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to read vault path %s: %w", path, err))
+			}
+
+			return nil, fmt.Errorf("unable to read vault path %s: %w", path, err)
+		}
+
+		if secret == nil {
+			return nil, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("vault path %s not found", path))
+		}
+
+		values := secret.Data
+
+		// KV v2 engines nest the secret fields under "data". With kvVersion 1
+		// the map is read as-is, so a field literally named "data" is not
+		// mistaken for the v2 nesting.
+		if h.kvVersion != "1" {
+			if nested, ok := secret.Data["data"].(map[string]interface{}); ok {
+				values = nested
+			} else if h.kvVersion == "2" {
+				return nil, fmt.Errorf("vault path %s did not return KV v2 data; check the path includes the data/ segment", path)
+			}
+		}
+
+		// Leased responses live in the lease cache instead, which serves
+		// them for the full lease rather than the short coalescing window.
+		if len(secret.LeaseID) > 0 && secret.LeaseDuration > 0 {
+			h.storeLease(path, secret, values)
+
+			return values, nil
+		}
+
+		h.readMu.Lock()
+		h.reads[cacheKey] = vaultReadEntry{values: values, fetched: time.Now()}
+		h.readMu.Unlock()
+
+		return values, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string]interface{}), nil
+}
+
+// issueCertificate mints a certificate from a PKI role, addressed as
+// pki/issue/<role>?common_name=<cn>&... with the query parameters passed to
+// the issue call as-is. The response is cached until the certificate
+// expires, so certificate, private_key and ca_chain placeholders of one CR
+// come from a single issuance, and the cert is re-issued once it runs out.
+func (h *VaultHandler) issueCertificate(ctx context.Context, path, key string) (redact.SensitiveString, error) {
+	var none redact.SensitiveString
+
+	issuePath, rawQuery, _ := strings.Cut(path, "?")
+
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return none, fmt.Errorf("vault pki path %s has invalid issue parameters: %w", issuePath, err)
+	}
+
+	payload := make(map[string]interface{}, len(params))
+
+	for name, values := range params {
+		payload[name] = values[0]
+	}
+
+	secret, err := h.client.Logical().WriteWithContext(ctx, issuePath, payload)
+	if err != nil {
+		var responseErr *vaultapi.ResponseError
+		if errors.As(err, &responseErr) &&
+			(responseErr.StatusCode == 401 || responseErr.StatusCode == 403) {
+			return none, errclass.Wrap(errclass.ProviderAuthFailed, fmt.Errorf("unable to issue certificate at vault path %s: %w", issuePath, err))
+		}
+
+		return none, fmt.Errorf("unable to issue certificate at vault path %s: %w", issuePath, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("vault pki path %s returned no certificate", issuePath))
+	}
+
+	// The response carries the expiry of the issued certificate; fall back
+	// to the lease duration for roles that lease their certificates.
+	var expires time.Time
+
+	switch expiration := secret.Data["expiration"].(type) {
+	case json.Number:
+		if unix, err := expiration.Int64(); err == nil {
+			expires = time.Unix(unix, 0)
+		}
+	case float64:
+		expires = time.Unix(int64(expiration), 0)
+	}
+
+	if expires.IsZero() && secret.LeaseDuration > 0 {
+		expires = time.Now().Add(time.Duration(secret.LeaseDuration) * time.Second)
+	}
+
+	h.leaseMu.Lock()
+	h.leases[path] = &vaultLease{
+		values:  secret.Data,
+		leaseID: secret.LeaseID,
+		expires: expires,
+	}
+	h.leaseMu.Unlock()
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return none, errclass.Wrap(errclass.KeyNotFound, fmt.Errorf("key %s not found in the certificate issued at vault path %s", key, issuePath))
+	}
+
+	return redact.Sensitive(vaultFieldString(value)), nil
+}
+
+// vaultFieldString renders one response field as a string. List fields such
+// as the ca_chain of an issued certificate become newline-joined PEM blocks.
+func vaultFieldString(value interface{}) string {
+	if items, ok := value.([]interface{}); ok {
+		parts := make([]string, 0, len(items))
+
+		for _, item := range items {
+			parts = append(parts, fmt.Sprintf("%v", item))
+		}
+
+		return strings.Join(parts, "\n")
+	}
+
+	return fmt.Sprintf("%v", value)
+}
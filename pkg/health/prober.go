@@ -0,0 +1,137 @@
+// Package health probes the connectivity of configured providers so failures
+// surface in /readyz before users notice stuck custom resources.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+)
+
+// Prober periodically verifies that every provider referenced by a
+// DataReplaceInline resource is reachable.
+type Prober struct {
+	Client client.Client
+
+	// Handlers is the shared handler pool, so probes reuse the same
+	// provider clients as reconciles instead of building fresh ones.
+	Handlers *handlers.Pool
+
+	// Interval between probe rounds. Defaults to one minute.
+	Interval time.Duration
+
+	mu       sync.Mutex
+	failures map[string]error
+}
+
+// Start runs probe rounds until the context is cancelled. It implements
+// manager.Runnable so the prober shares the manager's lifecycle and cache.
+func (p *Prober) Start(ctx context.Context) error {
+	if p.Interval <= 0 {
+		p.Interval = time.Minute
+	}
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.probe(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection keeps the prober running on every replica: readiness is
+// per pod, not per leader.
+func (p *Prober) NeedLeaderElection() bool {
+	return false
+}
+
+// Check reports the result of the last probe round and is registered as a
+// readyz checker.
+func (p *Prober) Check(_ *http.Request) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.failures) == 0 {
+		return nil
+	}
+
+	targets := make([]string, 0, len(p.failures))
+	for target := range p.failures {
+		targets = append(targets, target)
+	}
+
+	sort.Strings(targets)
+
+	return fmt.Errorf("providers unreachable: %s", strings.Join(targets, ", "))
+}
+
+// probe runs one round: it collects every provider referenced by the existing
+// custom resources and checks connectivity for those that support it.
+func (p *Prober) probe(ctx context.Context) {
+	logger := ctrl.Log.WithName("prober")
+
+	list := &datareplacev1alpha1.DataReplaceInlineList{}
+
+	if err := p.Client.List(ctx, list); err != nil {
+		logger.Error(err, "unable to list DataReplaceInline resources")
+		return
+	}
+
+	type target struct {
+		namespace string
+		provider  string
+		secret    string
+	}
+
+	targets := map[target]struct{}{}
+
+	for _, cr := range list.Items {
+		for _, manifest := range cr.Spec.Manifests {
+			for _, match := range placeholder.Find(manifest) {
+				targets[target{namespace: cr.Namespace, provider: match.Provider, secret: match.Secret}] = struct{}{}
+			}
+		}
+	}
+
+	failures := map[string]error{}
+
+	for t := range targets {
+		name := fmt.Sprintf("%s/%s/%s", t.provider, t.namespace, t.secret)
+
+		handler, _, err := p.Handlers.Get(ctx, t.namespace, t.provider, t.secret)
+		if err != nil {
+			failures[name] = err
+			continue
+		}
+
+		checker, ok := handler.(handlers.Checker)
+		if !ok {
+			continue
+		}
+
+		if err := checker.Check(ctx); err != nil {
+			failures[name] = err
+			logger.Info("provider unreachable", "provider", t.provider, "namespace", t.namespace, "secret", t.secret, "error", err.Error())
+		}
+	}
+
+	p.mu.Lock()
+	p.failures = failures
+	p.mu.Unlock()
+}
@@ -0,0 +1,102 @@
+// Package metrics defines the custom Prometheus metrics exported by the
+// operator through the controller-runtime metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// ReconcileDuration observes the wall time of whole reconciles.
+	ReconcileDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "datareplace_reconcile_duration_seconds",
+		Help:    "Duration of DataReplaceInline reconciles.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// RenderDuration observes the time spent rendering a single manifest,
+	// including all provider calls.
+	RenderDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "datareplace_render_duration_seconds",
+		Help:    "Duration of single manifest renders.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// ProviderCallDuration observes the latency of individual provider
+	// calls; the histogram count doubles as the total number of calls.
+	ProviderCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "datareplace_provider_call_duration_seconds",
+		Help:    "Duration of external provider calls.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	// ProviderCallErrors counts failed provider calls.
+	ProviderCallErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datareplace_provider_call_errors_total",
+		Help: "Total number of failed external provider calls.",
+	}, []string{"provider"})
+
+	// ResolutionErrors counts placeholder resolution failures broken down by
+	// provider type, provider secret, owning custom resource and error
+	// class, so dashboards can show which backend or which tenant is
+	// failing.
+	ResolutionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datareplace_resolution_errors_total",
+		Help: "Total number of placeholder resolution failures.",
+	}, []string{"provider", "secret", "cr_namespace", "cr_name", "class"})
+
+	// CacheHits counts lookups served from a cache, labeled by cache name
+	// (e.g. "client", "repo", "value").
+	CacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datareplace_cache_hits_total",
+		Help: "Total number of cache lookups served from the cache.",
+	}, []string{"cache"})
+
+	// CacheMisses counts lookups that had to go to the backing source.
+	CacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datareplace_cache_misses_total",
+		Help: "Total number of cache lookups that missed.",
+	}, []string{"cache"})
+
+	// CacheEntries reports the current number of entries per cache.
+	CacheEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "datareplace_cache_entries",
+		Help: "Current number of entries per cache.",
+	}, []string{"cache"})
+
+	// CacheEvictions counts entries removed from a cache before natural
+	// expiry (size pressure, explicit invalidation).
+	CacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datareplace_cache_evictions_total",
+		Help: "Total number of cache evictions.",
+	}, []string{"cache"})
+
+	// RepoCacheBytes reports the disk usage of cached git clones.
+	RepoCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "datareplace_repo_cache_bytes",
+		Help: "Disk usage of cached git clones in bytes.",
+	})
+
+	// AppliedResources counts the resources applied from rendered manifests.
+	AppliedResources = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "datareplace_applied_resources_total",
+		Help: "Total number of resources applied from rendered manifests.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcileDuration,
+		RenderDuration,
+		ProviderCallDuration,
+		ProviderCallErrors,
+		ResolutionErrors,
+		CacheHits,
+		CacheMisses,
+		CacheEntries,
+		CacheEvictions,
+		RepoCacheBytes,
+		AppliedResources,
+	)
+}
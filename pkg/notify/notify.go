@@ -0,0 +1,78 @@
+// Package notify delivers failure notifications to an external webhook so
+// render failures don't go unnoticed between GitOps syncs.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// Notification is the JSON payload POSTed to the webhook. Message is already
+// redacted: reconcile errors never contain resolved values.
+type Notification struct {
+	Time        time.Time `json:"time"`
+	CRNamespace string    `json:"crNamespace"`
+	CRName      string    `json:"crName"`
+	Reason      string    `json:"reason"`
+	Message     string    `json:"message"`
+}
+
+// Notifier POSTs notifications to a configurable webhook. With an empty URL
+// every call is a no-op.
+type Notifier struct {
+	url    string
+	client *http.Client
+	log    logr.Logger
+}
+
+// NewNotifier builds a Notifier for the given webhook URL, which may be
+// empty.
+func NewNotifier(url string) *Notifier {
+	return &Notifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		log:    ctrl.Log.WithName("notify"),
+	}
+}
+
+// NotifyFailure delivers one notification. Delivery problems are logged but
+// never fail the reconcile that triggered them.
+func (n *Notifier) NotifyFailure(ctx context.Context, notification Notification) {
+	if len(n.url) == 0 {
+		return
+	}
+
+	notification.Time = time.Now()
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		n.log.Error(err, "unable to encode notification")
+		return
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		n.log.Error(err, "unable to build webhook request")
+		return
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		n.log.Error(err, "unable to deliver notification")
+		return
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		n.log.Info("webhook rejected notification", "status", response.StatusCode)
+	}
+}
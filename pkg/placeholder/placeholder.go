@@ -0,0 +1,53 @@
+// Package placeholder is the single parser for manifest placeholders of the
+// form {{ <provider>:<secret>:<data> }}. All components share its
+// precompiled pattern instead of defining their own.
+package placeholder
+
+import "regexp"
+
+// regex matches one placeholder. It is compiled once at package load.
+var regex = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9]+):([^:\s]+):([^\s}]+)\s*\}\}`)
+
+// Placeholder is one parsed placeholder occurrence.
+type Placeholder struct {
+	// Raw is the full matched text, including braces. It contains no
+	// resolved values and is safe to log.
+	Raw string
+
+	// Provider is the provider type, e.g. "vault" or "git".
+	Provider string
+
+	// Secret names the provider configuration Secret.
+	Secret string
+
+	// Data is the provider specific selector, e.g. a Vault path and key or
+	// a dotted key into a git config file.
+	Data string
+}
+
+// Pattern returns the compiled placeholder pattern, for callers that need
+// regexp operations beyond Find such as bulk substitution.
+func Pattern() *regexp.Regexp {
+	return regex
+}
+
+// Find returns every placeholder in s, in order of occurrence.
+func Find(s string) []Placeholder {
+	matches := regex.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	placeholders := make([]Placeholder, 0, len(matches))
+
+	for _, match := range matches {
+		placeholders = append(placeholders, Placeholder{
+			Raw:      match[0],
+			Provider: match[1],
+			Secret:   match[2],
+			Data:     match[3],
+		})
+	}
+
+	return placeholders
+}
@@ -0,0 +1,44 @@
+package placeholder
+
+import "regexp"
+
+// refRegex matches one cross-resource output reference of the form
+// ${dri:<name>@status.outputs.<key>}. It is compiled once at package load.
+var refRegex = regexp.MustCompile(`\$\{dri:([a-zA-Z0-9.-]+)@status\.outputs\.([a-zA-Z0-9_.-]+)\}`)
+
+// Reference is one parsed cross-resource output reference. It reads a
+// declared, non-sensitive output published in the status of another
+// DataReplaceInline in the same namespace.
+type Reference struct {
+	// Raw is the full matched text. It contains no resolved values and is
+	// safe to log.
+	Raw string
+
+	// Name is the referenced DataReplaceInline.
+	Name string
+
+	// Output is the key looked up in status.outputs of the referenced
+	// resource.
+	Output string
+}
+
+// FindReferences returns every cross-resource output reference in s, in
+// order of occurrence.
+func FindReferences(s string) []Reference {
+	matches := refRegex.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	references := make([]Reference, 0, len(matches))
+
+	for _, match := range matches {
+		references = append(references, Reference{
+			Raw:    match[0],
+			Name:   match[1],
+			Output: match[2],
+		})
+	}
+
+	return references
+}
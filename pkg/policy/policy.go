@@ -0,0 +1,78 @@
+// Package policy enforces ProviderPolicy objects, which restrict the
+// provider backends each namespace may reference in placeholders.
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datareplacev1alpha1 "github.com/jaberchez/operator-data-replace-inline/api/v1alpha1"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/errclass"
+)
+
+// Checker evaluates ProviderPolicy objects for placeholder resolution.
+type Checker struct {
+	client client.Client
+}
+
+// NewChecker builds a Checker reading ProviderPolicy objects through c.
+func NewChecker(c client.Client) *Checker {
+	return &Checker{client: c}
+}
+
+// Allowed returns nil when the namespace may use the provider instance
+// configured by secretName. A namespace matched by no policy rule is
+// unrestricted; one matched by at least one rule may only use the providers
+// those rules list. Denials carry the ProviderForbidden reason.
+func (c *Checker) Allowed(ctx context.Context, namespace, provider, secretName string) error {
+	policies := &datareplacev1alpha1.ProviderPolicyList{}
+
+	if err := c.client.List(ctx, policies); err != nil {
+		return fmt.Errorf("unable to list provider policies: %w", err)
+	}
+
+	restricted := false
+
+	for _, policy := range policies.Items {
+		for _, rule := range policy.Spec.Rules {
+			if !matchesNamespace(rule.Namespaces, namespace) {
+				continue
+			}
+
+			restricted = true
+
+			if allowsProvider(rule.Providers, provider, secretName) {
+				return nil
+			}
+		}
+	}
+
+	if !restricted {
+		return nil
+	}
+
+	return errclass.Wrap(errclass.ProviderForbidden,
+		fmt.Errorf("provider %s:%s is not allowed in namespace %s by ProviderPolicy", provider, secretName, namespace))
+}
+
+func matchesNamespace(namespaces []string, namespace string) bool {
+	for _, entry := range namespaces {
+		if entry == "*" || entry == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func allowsProvider(providers []string, provider, secretName string) bool {
+	for _, entry := range providers {
+		if entry == "*" || entry == provider || entry == provider+":"+secretName {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+// Package redact keeps resolved provider values out of logs, errors and
+// events.
+package redact
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// obfuscationKey is a per-process random key used to keep wrapped values
+// XOR-masked while at rest in memory.
+var obfuscationKey [32]byte
+
+func init() {
+	if _, err := rand.Read(obfuscationKey[:]); err != nil {
+		panic(err)
+	}
+}
+
+// Mask returns a masked representation of a resolved value: four asterisks
+// followed by a short sha256 prefix. The prefix lets two occurrences of the
+// same value be correlated during debugging without revealing it.
+func Mask(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return "****" + hex.EncodeToString(sum[:])[:8]
+}
+
+// URL strips any userinfo (user:password@) from a URL so it can appear in
+// errors and logs. Unparsable input is returned unchanged.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+
+	parsed.User = nil
+
+	return parsed.String()
+}
+
+// SensitiveString wraps a resolved provider value. Every fmt and JSON
+// serialization of it is masked; the plaintext is only reachable through
+// Reveal, so new status, event or debug code cannot leak it by accident.
+// The value is held XOR-masked with a per-process random key while at rest,
+// so a core dump or /proc read of the operator process does not show it in
+// the clear, and Zero wipes the backing buffer once the value is no longer
+// needed.
+type SensitiveString struct {
+	cipher []byte
+	mask   string
+}
+
+// Sensitive wraps a resolved value.
+func Sensitive(value string) SensitiveString {
+	cipher := make([]byte, len(value))
+
+	for i := 0; i < len(value); i++ {
+		cipher[i] = value[i] ^ obfuscationKey[i%len(obfuscationKey)]
+	}
+
+	return SensitiveString{cipher: cipher, mask: Mask(value)}
+}
+
+// Reveal returns the plaintext value. Call sites of Reveal are the complete
+// audit surface for value exposure.
+func (s SensitiveString) Reveal() string {
+	plain := make([]byte, len(s.cipher))
+
+	for i := range s.cipher {
+		plain[i] = s.cipher[i] ^ obfuscationKey[i%len(obfuscationKey)]
+	}
+
+	return string(plain)
+}
+
+// Zero wipes the backing buffer. Copies of this SensitiveString share the
+// buffer and are wiped as well; Reveal on a wiped value returns garbage.
+func (s SensitiveString) Zero() {
+	for i := range s.cipher {
+		s.cipher[i] = 0
+	}
+}
+
+// String implements fmt.Stringer with the masked representation.
+func (s SensitiveString) String() string {
+	return s.mask
+}
+
+// GoString keeps %#v output masked as well.
+func (s SensitiveString) GoString() string {
+	return s.mask
+}
+
+// MarshalJSON serializes the masked representation.
+func (s SensitiveString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.mask)
+}
+
+// Line masks every one of the given resolved values found in a manifest
+// line, so the line can be logged or embedded in an error safely.
+func Line(line string, values []SensitiveString) string {
+	for _, value := range values {
+		plaintext := value.Reveal()
+		if len(plaintext) == 0 {
+			continue
+		}
+
+		line = strings.ReplaceAll(line, plaintext, Mask(plaintext))
+	}
+
+	return line
+}
@@ -0,0 +1,50 @@
+// Package tracing configures OpenTelemetry tracing for the operator.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the operator in exported spans.
+const tracerName = "github.com/jaberchez/operator-data-replace-inline"
+
+// Setup configures the global tracer provider. The exporter is configured
+// through the standard OTEL_EXPORTER_OTLP_* environment variables; when no
+// endpoint is set, tracing stays disabled and spans are no-ops. The returned
+// function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	if len(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")) == 0 &&
+		len(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("operator-data-replace-inline"),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer used for operator spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
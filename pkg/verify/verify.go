@@ -0,0 +1,47 @@
+// Package verify checks cosign blob signatures over manifest content.
+//
+// "cosign sign-blob" signs the sha256 digest of a file with an ECDSA P-256
+// key and emits the ASN.1 signature base64 encoded, so verification only
+// needs the standard library.
+package verify
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+)
+
+// Signature verifies a base64 cosign blob signature over content against a
+// PEM encoded cosign public key.
+func Signature(publicKeyPEM []byte, content, signature string) error {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return fmt.Errorf("public key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("unable to parse public key: %w", err)
+	}
+
+	key, ok := parsed.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key is not an ECDSA key")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("unable to decode signature: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(content))
+
+	if !ecdsa.VerifyASN1(key, digest[:], raw) {
+		return fmt.Errorf("signature does not match content")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package webhook resolves placeholders at admission time, so teams can use
+// the placeholder syntax directly in their own resources without wrapping
+// them in a DataReplaceInline.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/jaberchez/operator-data-replace-inline/pkg/handlers"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/placeholder"
+	"github.com/jaberchez/operator-data-replace-inline/pkg/policy"
+)
+
+// InjectAnnotation opts a resource into placeholder resolution at admission.
+const InjectAnnotation = "datareplaceinline/inject"
+
+// PlaceholderInjector is a mutating admission handler that resolves
+// placeholders inline in any resource annotated with
+// "datareplaceinline/inject: true". Provider Secrets are read from the
+// namespace of the admitted object, and ProviderPolicy restrictions apply
+// exactly as they do during CR rendering.
+type PlaceholderInjector struct {
+	Handlers *handlers.Pool
+	Policy   *policy.Checker
+}
+
+// Handle implements admission.Handler.
+func (i *PlaceholderInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &unstructured.Unstructured{}
+
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unable to decode object: %w", err))
+	}
+
+	if obj.GetAnnotations()[InjectAnnotation] != "true" {
+		return admission.Allowed("not opted in")
+	}
+
+	// Placeholders are substituted on the raw JSON document, so they work in
+	// any field of any resource.
+	mutated := string(req.Object.Raw)
+
+	matches := placeholder.Find(mutated)
+	if len(matches) == 0 {
+		return admission.Allowed("no placeholders")
+	}
+
+	// Identical placeholders resolve once, mirroring the render path.
+	resolved := map[string]bool{}
+
+	for _, match := range matches {
+		if resolved[match.Raw] {
+			continue
+		}
+
+		if err := i.Policy.Allowed(ctx, req.Namespace, match.Provider, match.Secret); err != nil {
+			return admission.Denied(err.Error())
+		}
+
+		handler, _, err := i.Handlers.Get(ctx, req.Namespace, match.Provider, match.Secret)
+		if err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity,
+				fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err))
+		}
+
+		value, err := handler.GetData(ctx, match.Data)
+		if err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity,
+				fmt.Errorf("unable to resolve placeholder %s: %w", match.Raw, err))
+		}
+
+		mutated = strings.ReplaceAll(mutated, match.Raw, jsonEscape(value.Reveal()))
+		resolved[match.Raw] = true
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, []byte(mutated))
+}
+
+// jsonEscape escapes a resolved value for substitution inside a JSON string
+// literal.
+func jsonEscape(value string) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	return string(encoded[1 : len(encoded)-1])
+}